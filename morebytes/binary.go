@@ -0,0 +1,115 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package morebytes
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// readFixed returns the next n bytes of the File and advances the offset past
+// them. If fewer than n bytes remain, readFixed returns io.EOF (if none remain)
+// or io.ErrUnexpectedEOF, and does not advance the offset.
+func (f *File) readFixed(n int) ([]byte, error) {
+	b := f.next()
+	if len(b) < n {
+		if len(b) == 0 {
+			return nil, io.EOF
+		}
+		return nil, io.ErrUnexpectedEOF
+	}
+	f.offset += int64(n)
+	return b[:n], nil
+}
+
+// ReadUint16 reads a uint16 encoded in the given byte order from the current
+// offset, and advances the offset past it.
+//
+// If no bytes remain, ReadUint16 returns io.EOF. If only some of the required
+// bytes remain, it returns io.ErrUnexpectedEOF. In either case, the offset is
+// not changed.
+func (f *File) ReadUint16(order binary.ByteOrder) (uint16, error) {
+	b, err := f.readFixed(2)
+	if err != nil {
+		return 0, err
+	}
+	return order.Uint16(b), nil
+}
+
+// ReadUint32 is like ReadUint16, but reads a uint32.
+func (f *File) ReadUint32(order binary.ByteOrder) (uint32, error) {
+	b, err := f.readFixed(4)
+	if err != nil {
+		return 0, err
+	}
+	return order.Uint32(b), nil
+}
+
+// ReadUint64 is like ReadUint16, but reads a uint64.
+func (f *File) ReadUint64(order binary.ByteOrder) (uint64, error) {
+	b, err := f.readFixed(8)
+	if err != nil {
+		return 0, err
+	}
+	return order.Uint64(b), nil
+}
+
+// ReadInt16 is like ReadUint16, but reads an int16.
+func (f *File) ReadInt16(order binary.ByteOrder) (int16, error) {
+	v, err := f.ReadUint16(order)
+	return int16(v), err
+}
+
+// ReadInt32 is like ReadUint16, but reads an int32.
+func (f *File) ReadInt32(order binary.ByteOrder) (int32, error) {
+	v, err := f.ReadUint32(order)
+	return int32(v), err
+}
+
+// ReadInt64 is like ReadUint16, but reads an int64.
+func (f *File) ReadInt64(order binary.ByteOrder) (int64, error) {
+	v, err := f.ReadUint64(order)
+	return int64(v), err
+}
+
+// WriteUint16 writes v encoded in the given byte order at the current offset,
+// as if by Write.
+func (f *File) WriteUint16(order binary.ByteOrder, v uint16) error {
+	var b [2]byte
+	order.PutUint16(b[:], v)
+	_, err := f.Write(b[:])
+	return err
+}
+
+// WriteUint32 is like WriteUint16, but writes a uint32.
+func (f *File) WriteUint32(order binary.ByteOrder, v uint32) error {
+	var b [4]byte
+	order.PutUint32(b[:], v)
+	_, err := f.Write(b[:])
+	return err
+}
+
+// WriteUint64 is like WriteUint16, but writes a uint64.
+func (f *File) WriteUint64(order binary.ByteOrder, v uint64) error {
+	var b [8]byte
+	order.PutUint64(b[:], v)
+	_, err := f.Write(b[:])
+	return err
+}
+
+// WriteInt16 is like WriteUint16, but writes an int16.
+func (f *File) WriteInt16(order binary.ByteOrder, v int16) error {
+	return f.WriteUint16(order, uint16(v))
+}
+
+// WriteInt32 is like WriteUint16, but writes an int32.
+func (f *File) WriteInt32(order binary.ByteOrder, v int32) error {
+	return f.WriteUint32(order, uint32(v))
+}
+
+// WriteInt64 is like WriteUint16, but writes an int64.
+func (f *File) WriteInt64(order binary.ByteOrder, v int64) error {
+	return f.WriteUint64(order, uint64(v))
+}
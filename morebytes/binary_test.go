@@ -0,0 +1,61 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package morebytes_test
+
+import (
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/bcmills/more/morebytes"
+)
+
+func TestFileBinary(t *testing.T) {
+	f := new(morebytes.File)
+	if err := f.WriteUint16(binary.BigEndian, 0x0102); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.WriteInt32(binary.LittleEndian, -2); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.WriteUint64(binary.BigEndian, 0x0102030405060708); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.WriteInt16(binary.LittleEndian, -3); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "\x01\x02" + "\xfe\xff\xff\xff" + "\x01\x02\x03\x04\x05\x06\x07\x08" + "\xfd\xff"
+	if f.String() != want {
+		t.Fatalf("contents = %q; want %q", f.String(), want)
+	}
+
+	f.Rewind()
+	if v, err := f.ReadUint16(binary.BigEndian); v != 0x0102 || err != nil {
+		t.Errorf("ReadUint16 = %#x, %v; want 0x102, <nil>", v, err)
+	}
+	if v, err := f.ReadInt32(binary.LittleEndian); v != -2 || err != nil {
+		t.Errorf("ReadInt32 = %v, %v; want -2, <nil>", v, err)
+	}
+	if v, err := f.ReadUint64(binary.BigEndian); v != 0x0102030405060708 || err != nil {
+		t.Errorf("ReadUint64 = %#x, %v; want 0x102030405060708, <nil>", v, err)
+	}
+
+	// Only 2 bytes remain, so a 4-byte read is short and does not advance.
+	if _, err := f.ReadUint32(binary.LittleEndian); err != io.ErrUnexpectedEOF {
+		t.Errorf("ReadUint32 with 2 bytes remaining: %v; want ErrUnexpectedEOF", err)
+	}
+	if v, err := f.ReadInt16(binary.LittleEndian); v != -3 || err != nil {
+		t.Errorf("ReadInt16 = %v, %v; want -3, <nil>", v, err)
+	}
+	if _, err := f.ReadInt64(binary.LittleEndian); err != io.EOF {
+		t.Errorf("ReadInt64 at end of File: %v; want EOF", err)
+	}
+
+	ff := morebytes.NewFixedFile(make([]byte, 0, 3))
+	if err := ff.WriteUint32(binary.BigEndian, 1); err != morebytes.ErrFileSizeLimit {
+		t.Errorf("WriteUint32 to a 3-byte fixed File: %v; want ErrFileSizeLimit", err)
+	}
+}
@@ -7,7 +7,10 @@ package morebytes
 import (
 	"bytes"
 	"errors"
+	"fmt"
+	"hash"
 	"io"
+	"strconv"
 	"sync"
 	"unicode/utf8"
 )
@@ -16,6 +19,13 @@ import (
 // cause its buffer's length to exceed its maximum capacity.
 var ErrFileSizeLimit = errors.New("morebytes: File size limit exceeded")
 
+// ErrReadOnly indicates an attempt to modify a read-only File.
+var ErrReadOnly = errors.New("morebytes: File is read-only")
+
+// errInvalidWrite means that a write returned an impossible count,
+// as in the io package.
+var errInvalidWrite = errors.New("invalid write result")
+
 // A File is an io.ReadWriteSeeker (like os.File) that reads, writes, and seeks
 // within a slice of bytes. The slice backing the File may be either fixed or
 // reallocated on demand; the zero File reallocates on demand.
@@ -39,15 +49,11 @@ var ErrFileSizeLimit = errors.New("morebytes: File size limit exceeded")
 // 	- It does not provide a Len method because it would be unclear whether Len
 // 	  reports the length of the backing slice or the number of bytes remaining
 // 	  to be read after the current offset. Use Size for the former, and
-// 	  subtract Seek(0, io.SeekCurrent) for the latter.
-//
-// 	- It does not implement io.ReaderFrom because a File with a fixed backing
-// 	  slice would not be able to detect io.EOF when the backing slice is exactly
-// 	  full.
+// 	  Remaining for the latter.
 //
 // 	- It does not provide a Grow method because a File with a fixed backing
-// 	  slice can fail to grow beyond its capacity; instead, use Truncate, which
-// 	  returns an explicit error.
+// 	  slice can fail to grow beyond its capacity; instead, use Reserve or
+// 	  Truncate, which return an explicit error.
 //
 // 	- It does not provide a nilladic Reset method because that would be
 // 	  redundant with Truncate — the Reset([]byte) method from bytes.Reader is
@@ -57,9 +63,27 @@ type File struct {
 	buf       []byte
 	offset    int64 // distinct from len(buf) because Seek is explicitly allowed to set it to an arbitrary positive int64
 	fixed     bool
+	ring      bool  // if true, writes beyond the size limit discard the oldest data
+	limited   bool  // if true, limit further restricts the size limit
+	limit     int64 // the size limit set by SetSizeLimit, if limited
+	readOnly  bool  // if true, methods that would modify the data fail with ErrReadOnly
+	shared    bool  // if true, buf is shared with another File and must be copied before modifying
 	writeAtMu sync.RWMutex
+
+	ringBase []byte // for a ring File that has discarded data, the array of which buf is a window
+	ringHead int    // the index in ringBase at which buf begins
+
+	strictUnread bool  // if true, UnreadByte and UnreadRune must immediately follow a read
+	lastRead     int   // the size of the rune read by the last ReadRune, opReadByte, or opInvalid
+	lastReadEnd  int64 // the offset immediately after the last ReadByte or ReadRune
 }
 
+// Values of File.lastRead other than rune sizes.
+const (
+	opInvalid  = 0  // the last operation was not ReadByte or ReadRune
+	opReadByte = -1 // the last operation was ReadByte
+)
+
 const (
 	intSize = 32 << (^uint(0) >> 63) // 32 or 64
 
@@ -92,22 +116,229 @@ func NewFixedFile(b []byte) *File {
 	return f
 }
 
+// NewRingFile returns a new File backed by slice b that retains only the most
+// recently written data.
+//
+// As with NewFixedFile, the maximum size of the File is the capacity of its
+// backing slice. However, a Write, WriteString, WriteByte, WriteRune, or
+// ReadFrom that would grow the File beyond that size instead discards the
+// oldest data from the start of the File (moving the remaining data and the
+// offset back accordingly) so that the most recent cap(b) bytes are retained,
+// from oldest to newest.
+//
+// The first time it discards data, a ring File moves its retained data to a
+// newly-allocated array of twice cap(b) bytes, so subsequent writes are no
+// longer visible through b. It then discards data by advancing a window of
+// cap(b) bytes over that array, moving the data back to the start of the array
+// only when the window reaches its end, so discarding data requires amortized
+// constant time per byte.
+//
+// Other methods that modify the File, such as WriteAt, Insert, and Truncate,
+// do not discard data and behave as they would for a fixed File.
+//
+// The initial offset is 0, size is len(b), and capacity is cap(b).
+func NewRingFile(b []byte) *File {
+	f := &File{fixed: true, ring: true}
+	f.Reset(b)
+	return f
+}
+
 // Reset resets the writer to be backed by b, also resetting
 // the current offset to 0, size to len(b), and capacity to cap(b).
 func (f *File) Reset(b []byte) {
 	*f = File{
-		buf:   b,
-		fixed: f.fixed,
+		buf:      b,
+		fixed:    f.fixed,
+		ring:     f.ring,
+		limited:  f.limited,
+		limit:    f.limit,
+		readOnly: f.readOnly,
+
+		strictUnread: f.strictUnread,
 	}
 }
 
+// Clone returns a new File with the same contents, offset, and size limit as
+// f, backed by a newly-allocated copy of f's data.
+//
+// Unlike NewFile(f.Bytes()), writes to the returned File do not affect f, and
+// vice-versa. The returned File is never read-only, so Clone can be used to
+// obtain a writable copy of a read-only File.
+func (f *File) Clone() *File {
+	c := cap(f.buf)
+	if !f.fixed {
+		c = len(f.buf)
+	}
+	buf := make([]byte, len(f.buf), c)
+	copy(buf, f.buf)
+	return &File{
+		buf:     buf,
+		offset:  f.offset,
+		fixed:   f.fixed,
+		ring:    f.ring,
+		limited: f.limited,
+		limit:   f.limit,
+
+		strictUnread: f.strictUnread,
+	}
+}
+
+// Reader returns a bytes.Reader over a snapshot of the File's current contents
+// (up to its current size), starting at offset 0. The returned Reader has its
+// own offset, independent of f's, and also implements io.ReaderAt and
+// io.Seeker.
+//
+// The Reader sees exactly the bytes that f contained when Reader was called,
+// even if f is subsequently modified: like Fork, Reader shares f's backing
+// slice, and the next operation that modifies f first copies the shared data
+// to a new backing slice. That first modification requires time proportional
+// to the size of the File. (A File returned by an earlier call to Section
+// still aliases the shared data, and must not be written while the Reader is
+// in use.)
+//
+// If f is fixed or read-only, Reader instead copies f's contents immediately:
+// a fixed File must keep writing to the slice it was created with, and a File
+// returned by Section or ReadOnly aliases data that may still be modified
+// through the File from which it was derived.
+//
+// The returned Reader may be used concurrently with any use of f, so the owner
+// of f can continue to write to it while another goroutine consumes the
+// snapshot.
+func (f *File) Reader() *bytes.Reader {
+	if f.fixed || f.readOnly {
+		f.writeAtMu.RLock()
+		defer f.writeAtMu.RUnlock()
+		return bytes.NewReader(f.BytesCopy())
+	}
+
+	f.writeAtMu.Lock()
+	defer f.writeAtMu.Unlock()
+	f.shared = true
+	return bytes.NewReader(f.Bytes())
+}
+
+// Fork returns a new File with the same contents, offset, and size limit as f
+// that initially shares f's backing slice. The first operation that modifies
+// the returned File (such as Write, WriteAt, or Truncate) first copies the
+// shared data to a new backing slice, after which the two Files are
+// independent. That first modification requires time proportional to the size
+// of the File.
+//
+// f itself must not be modified while the returned File may still share its
+// data. Multiple goroutines may call Fork concurrently on an f that is not
+// being modified. The returned File is never read-only.
+func (f *File) Fork() *File {
+	return &File{
+		buf:     f.buf,
+		offset:  f.offset,
+		fixed:   f.fixed,
+		ring:    f.ring,
+		limited: f.limited,
+		limit:   f.limit,
+		shared:  true,
+
+		strictUnread: f.strictUnread,
+	}
+}
+
+// unshare copies f's data to a new backing slice if it may be shared with
+// another File, so that it can be modified safely.
+func (f *File) unshare() {
+	if !f.shared {
+		return
+	}
+	c := cap(f.buf)
+	if !f.fixed {
+		c = len(f.buf)
+	}
+	buf := make([]byte, len(f.buf), c)
+	copy(buf, f.buf)
+	f.buf = buf
+	f.shared = false
+	f.ringBase = nil
+}
+
 // Bytes returns the File's current backing data, independent of the current
 // offset, with its length equal to the current size.
 //
 // Further writes to the File will continue to overwrite the underlying data,
 // but not the length of the returned slice.
+//
+// The capacity of the returned slice is equal to its length, so appending to it
+// always reallocates rather than overwriting bytes beyond the File's size.
+// To obtain a slice that does not alias the File at all, use BytesCopy.
 func (f *File) Bytes() []byte {
-	return f.buf[:f.Size()]
+	size := f.Size()
+	return f.buf[:size:size]
+}
+
+// BytesCopy returns a newly-allocated copy of the File's current data,
+// independent of the current offset, with its length equal to the current size.
+// Subsequent writes to the File do not affect the returned slice, nor vice-versa.
+func (f *File) BytesCopy() []byte {
+	return append([]byte(nil), f.Bytes()...)
+}
+
+// Section returns a new fixed File backed by the portion of f's data in the
+// range [off, off+n), clamped to f's current size. The returned File has its
+// own offset, starting at 0, and its size limit is the length of the range,
+// so writes through it are confined to that range.
+//
+// Section panics if off or n is negative.
+//
+// The returned File aliases f's backing slice: writes through either File are
+// visible to the other, until f's backing slice is reallocated (for example,
+// by a Write that grows f beyond its capacity).
+func (f *File) Section(off, n int64) *File {
+	if off < 0 {
+		panic("morebytes: Section: negative offset")
+	}
+	if n < 0 {
+		panic("morebytes: Section: negative length")
+	}
+	size := f.Size()
+	if off > size {
+		off = size
+	}
+	if n > size-off {
+		n = size - off
+	}
+	end := off + n
+	g := NewFixedFile(f.buf[off:end:end])
+	g.readOnly = f.readOnly
+	g.shared = f.shared
+	return g
+}
+
+// SectionReader returns an io.SectionReader that reads from f (using ReadAt)
+// starting at offset off and stops with io.EOF after n bytes. It is intended for
+// APIs that require the concrete *io.SectionReader type; otherwise, Section is
+// usually more efficient.
+//
+// Unlike Section, SectionReader does not copy or alias the data at the time of
+// the call: each read observes f's contents at the time of that read, including
+// data written after SectionReader returns and data beyond f's current size if
+// f later grows. The returned reader does not change f's offset.
+//
+// Reads from the returned reader may be made concurrently with each other and
+// with calls to f's ReadAt, WriteAt, and ReadFromAt methods, but not with other
+// methods that modify f, such as Write or Truncate.
+func (f *File) SectionReader(off, n int64) *io.SectionReader {
+	return io.NewSectionReader(f, off, n)
+}
+
+// ReadOnly returns a new File that reads the same data as f, but whose methods
+// that would modify the data (such as Write, WriteAt, Truncate, and ReadFrom)
+// fail with ErrReadOnly. The returned File has its own offset, starting at 0,
+// and its size is fixed at f's current size.
+//
+// As with Section, the returned File aliases f's backing slice, so writes
+// through f remain visible through it. Slices returned by methods such as
+// Bytes, Next, and Peek also alias the data, and must not be modified.
+func (f *File) ReadOnly() *File {
+	g := f.Section(0, f.Size())
+	g.readOnly = true
+	return g
 }
 
 // Cap returns the capacity of the File's underlying byte slice;
@@ -116,15 +347,60 @@ func (f *File) Cap() int {
 	return cap(f.buf)
 }
 
+// Reserve ensures that the File's backing slice has capacity for at least n
+// more bytes beyond its current size, reallocating the slice if needed.
+// It does not change the size or offset of the File.
+//
+// If the reserved size would exceed f's size limit, Reserve returns
+// ErrFileSizeLimit and leaves the capacity unchanged.
+func (f *File) Reserve(n int) error {
+	if n < 0 {
+		return errors.New("Reserve: negative count")
+	}
+	size := f.Size() + int64(n)
+	if size > f.SizeLimit() {
+		return ErrFileSizeLimit
+	}
+	f.reserve(size)
+	return nil
+}
+
+// Fixed reports whether f has a fixed backing slice (as for a File returned by
+// NewFixedFile, NewRingFile, Section, or ReadOnly), so that its size can
+// never exceed the capacity of that slice. If Fixed returns false, f
+// reallocates its backing slice as needed to grow, up to its size limit.
+func (f *File) Fixed() bool {
+	return f.fixed
+}
+
 // SizeLimit returns the maximum allowed size of the File's data.
 //
 // The result can always be represented without overflow as an int:
 // SizeLimit returns an int64 only to match the return type of Size.
 func (f *File) SizeLimit() int64 {
+	limit := int64(maxInt)
 	if f.fixed {
-		return int64(cap(f.buf))
+		limit = int64(cap(f.buf))
+	}
+	if f.limited && f.limit < limit {
+		limit = f.limit
 	}
-	return int64(maxInt)
+	return limit
+}
+
+// SetSizeLimit sets the maximum allowed size of the File's data to limit.
+// Operations that would grow the File beyond that size fail with
+// ErrFileSizeLimit, as for a fixed File; however, a File that is not fixed
+// still reallocates its backing slice as needed up to the limit.
+//
+// For a fixed File, the effective limit is the smaller of limit and the
+// capacity of its backing slice. If limit is negative, SetSizeLimit removes
+// any limit previously set.
+//
+// SetSizeLimit does not truncate data already beyond the new limit.
+func (f *File) SetSizeLimit(limit int64) {
+	f.limited = limit >= 0
+	f.limit = limit
 }
 
 // Size returns the current size of the File's data.
@@ -135,6 +411,102 @@ func (f *File) Size() int64 {
 	return int64(len(f.buf))
 }
 
+// Remaining returns the number of bytes between the current offset and the
+// end of the File's data; that is, the number of bytes that remain to be read.
+//
+// If the offset has been moved past the end of the data (for example, by
+// Seek), Remaining returns 0.
+func (f *File) Remaining() int64 {
+	if n := f.Size() - f.offset; n > 0 {
+		return n
+	}
+	return 0
+}
+
+// Available returns the number of bytes that can be written at the current
+// offset before reaching f's size limit; that is, SizeLimit() - Offset(),
+// or 0 if the offset is beyond the limit or f is read-only.
+//
+// A write to a ring File may exceed Available by discarding older data.
+func (f *File) Available() int64 {
+	if f.readOnly {
+		return 0
+	}
+	if n := f.SizeLimit() - f.offset; n > 0 {
+		return n
+	}
+	return 0
+}
+
+// Full reports whether the File's data has reached its size limit, so that no
+// further data can be appended to it (except by discarding older data from a
+// ring File).
+//
+// Full helps to detect truncation when a fixed File is filled by copying from a
+// stream: if the File is not full after the copy, it holds the complete stream;
+// if it is full, the stream may have been truncated (or may have exactly fit),
+// and the caller can check by attempting to read further from the stream.
+func (f *File) Full() bool {
+	return f.Size() >= f.SizeLimit()
+}
+
+// AvailableBuffer returns an empty slice beginning at the current offset, whose
+// capacity is the spare capacity of the backing slice (up to f's size limit).
+// The slice is intended to be appended to and either passed to an immediately
+// succeeding Write call, which then does not need to copy or reallocate, or
+// followed by a call to Commit with its length, which does not copy at all.
+//
+// The buffer is only valid until the next call to any other method of f.
+func (f *File) AvailableBuffer() []byte {
+	if f.readOnly {
+		return nil
+	}
+	f.unshare()
+	end := f.availableEnd()
+	if f.offset > end {
+		return nil
+	}
+	return f.buf[f.offset:f.offset:end]
+}
+
+// availableEnd returns the end of the region that AvailableBuffer may expose:
+// the capacity of the backing slice, or f's size limit if that is smaller.
+func (f *File) availableEnd() int64 {
+	end := int64(cap(f.buf))
+	if limit := f.SizeLimit(); end > limit {
+		end = limit
+	}
+	return end
+}
+
+// Commit records that the first n bytes of the slice most recently returned by
+// AvailableBuffer have been filled in, as if they had been passed to Write:
+// it advances the offset by n and, if needed, extends the size to match.
+//
+// Commit must be called before any other method of f after the call to
+// AvailableBuffer. It panics if n is negative or exceeds the capacity of
+// the buffer.
+func (f *File) Commit(n int) {
+	if n == 0 {
+		return
+	}
+	end := f.availableEnd()
+	if n < 0 || f.readOnly || f.offset > end || int64(n) > end-f.offset {
+		panic("Commit: n out of range")
+	}
+
+	if old := int64(len(f.buf)); f.offset > old {
+		// Zero-fill any gap between the end of the data and the start of the
+		// committed bytes, as Write would.
+		f.buf = f.buf[:f.offset]
+		zero(f.buf[old:])
+	}
+	f.offset += int64(n)
+	if f.offset > int64(len(f.buf)) {
+		f.buf = f.buf[:f.offset]
+	}
+}
+
 // String returns the contents of the complete file (up to its size)
 // as a string. If the *File is a nil pointer, it returns "<nil>".
 func (f *File) String() string {
@@ -144,6 +516,119 @@ func (f *File) String() string {
 	return string(f.Bytes())
 }
 
+// Format implements fmt.Formatter.
+//
+// The %s and %v verbs format the contents of the File as String would, and the
+// %q, %x, and %X verbs format them as they would a []byte. A precision limits
+// the output as for a string (for example, %.20q formats at most 20 runes),
+// without copying the rest of the File's contents. The %#v verb formats the
+// File as GoString would.
+func (f *File) Format(s fmt.State, verb rune) {
+	if verb == 'v' && s.Flag('#') {
+		io.WriteString(s, f.GoString())
+		return
+	}
+	if verb == 'v' {
+		verb = 's'
+	}
+
+	b := []byte("<nil>")
+	if f != nil {
+		b = f.Bytes()
+	}
+
+	directive := []byte{'%'}
+	for _, flag := range "+-# 0" {
+		if s.Flag(int(flag)) {
+			directive = append(directive, byte(flag))
+		}
+	}
+	if width, ok := s.Width(); ok {
+		directive = strconv.AppendInt(directive, int64(width), 10)
+	}
+	if prec, ok := s.Precision(); ok {
+		directive = append(directive, '.')
+		directive = strconv.AppendInt(directive, int64(prec), 10)
+
+		// The precision counts runes (or, for %x and %X, bytes), so the output
+		// depends on at most prec*utf8.UTFMax bytes of the contents.
+		if max := prec * utf8.UTFMax; prec >= 0 && max/utf8.UTFMax == prec && len(b) > max {
+			b = b[:max]
+		}
+	}
+	directive = append(directive, string(verb)...)
+
+	fmt.Fprintf(s, string(directive), b)
+}
+
+// goStringMax is the maximum number of bytes of a File's contents
+// included in the result of GoString.
+const goStringMax = 64
+
+// GoString returns a bounded description of the File, including its size,
+// offset, and (up to) the first 64 bytes of its contents, for use in
+// diagnostics. It implements fmt.GoStringer.
+func (f *File) GoString() string {
+	if f == nil {
+		return "(*morebytes.File)(nil)"
+	}
+	b := f.Bytes()
+	ellipsis := ""
+	if len(b) > goStringMax {
+		b = b[:goStringMax]
+		ellipsis = "..."
+	}
+	return fmt.Sprintf("morebytes.File(size=%d, offset=%d, %q%s)", f.Size(), f.offset, b, ellipsis)
+}
+
+// Equal reports whether f and g have the same contents, up to their respective
+// sizes, regardless of their offsets and capacities.
+// Two nil Files are equal, but a nil File is not equal to an empty one.
+func (f *File) Equal(g *File) bool {
+	if f == nil || g == nil {
+		return f == g
+	}
+	return bytes.Equal(f.Bytes(), g.Bytes())
+}
+
+// HashTo writes the complete contents of the File (up to its size) to h and
+// returns the resulting h.Sum(nil). Unlike WriteTo, HashTo does not depend on
+// or change the current offset.
+func (f *File) HashTo(h hash.Hash) []byte {
+	h.Write(f.Bytes()) // A hash.Hash never returns an error from Write.
+	return h.Sum(nil)
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+// It returns a copy of the contents of the File, up to its size.
+func (f *File) MarshalBinary() ([]byte, error) {
+	return f.BytesCopy(), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+// It replaces the contents of the File with a copy of b and resets the offset
+// to 0.
+//
+// If len(b) exceeds f's size limit, UnmarshalBinary returns ErrFileSizeLimit
+// and leaves the File unchanged.
+func (f *File) UnmarshalBinary(b []byte) error {
+	if f.readOnly {
+		return ErrReadOnly
+	}
+	if int64(len(b)) > f.SizeLimit() {
+		return ErrFileSizeLimit
+	}
+	f.unshare()
+	if f.fixed {
+		// Copy into the existing backing slice to preserve the size limit.
+		f.Reset(f.buf[:len(b)])
+		copy(f.buf, b)
+	} else {
+		f.Reset(append([]byte(nil), b...))
+	}
+	return nil
+}
+
 // Next returns the portion of the File's backing slice containing the next n
 // bytes starting at the current offset, or nil if the current offset is greater
 // than the capacity of the file, advancing the offset as if the bytes had been
@@ -158,13 +643,58 @@ func (f *File) Next(n int) []byte {
 	return buf[:n]
 }
 
+// Peek returns the portion of the File's backing slice containing the next n
+// bytes starting at the current offset, without advancing the offset.
+// If there are fewer than n bytes between the current offset and size, Peek
+// returns whatever is available along with io.EOF.
+//
+// The returned slice aliases the File's backing slice, so its contents may be
+// overwritten by a subsequent write to the File.
+func (f *File) Peek(n int) ([]byte, error) {
+	if n < 0 {
+		return nil, errors.New("Peek: negative count")
+	}
+	buf := f.next()
+	if n > len(buf) {
+		return buf, io.EOF
+	}
+	return buf[:n], nil
+}
+
+// IndexByte returns the offset (relative to the start of the File) of the first
+// instance of c in the data between the current offset and size, or -1 if c is
+// not present. It does not change the offset.
+func (f *File) IndexByte(c byte) int {
+	i := bytes.IndexByte(f.next(), c)
+	if i < 0 {
+		return -1
+	}
+	return int(f.offset) + i
+}
+
+// Index returns the offset (relative to the start of the File) of the first
+// instance of sep in the data between the current offset and size, or -1 if sep
+// is not present. It does not change the offset.
+func (f *File) Index(sep []byte) int {
+	i := bytes.Index(f.next(), sep)
+	if i < 0 {
+		return -1
+	}
+	return int(f.offset) + i
+}
+
 // next returns the portion of the backing store in the range [offset, size).
 func (f *File) next() []byte {
+	return f.dataFrom(f.offset)
+}
+
+// dataFrom returns the portion of the backing store in the range [off, size).
+func (f *File) dataFrom(off int64) []byte {
 	size := f.Size()
-	if f.offset >= size {
+	if off >= size {
 		return nil
 	}
-	return f.buf[f.offset:size]
+	return f.buf[off:size]
 }
 
 // Read implements the io.Reader interface.
@@ -182,19 +712,30 @@ func (f *File) Read(b []byte) (n int, err error) {
 func (f *File) ReadByte() (byte, error) {
 	buf := f.next()
 	if len(buf) < 1 {
+		f.lastRead = opInvalid
 		return 0, io.EOF
 	}
 	b := buf[0]
 	f.offset += 1
+	f.lastRead, f.lastReadEnd = opReadByte, f.offset
 	return b, nil
 }
 
 // UnreadByte implements the io.ByteScanner interface.
+//
+// By default, UnreadByte moves the offset back by one byte regardless of the
+// preceding operation. If strict unreading is enabled (see SetStrictUnread),
+// UnreadByte instead returns an error unless the preceding operation on f was
+// a ReadByte or ReadRune.
 func (f *File) UnreadByte() error {
+	if f.strictUnread && !f.canUnread() {
+		return errors.New("UnreadByte: previous operation was not a successful ReadByte or ReadRune")
+	}
 	if f.offset <= 0 {
 		return errors.New("UnreadByte: no bytes to unread")
 	}
 	f.offset -= 1
+	f.lastRead = opInvalid
 	return nil
 }
 
@@ -202,15 +743,31 @@ func (f *File) UnreadByte() error {
 func (f *File) ReadRune() (r rune, rSize int, err error) {
 	buf := f.next()
 	if len(buf) < 1 {
+		f.lastRead = opInvalid
 		return 0, 0, io.EOF
 	}
 	r, rSize = utf8.DecodeRune(buf)
 	f.offset += int64(rSize)
+	f.lastRead, f.lastReadEnd = rSize, f.offset
 	return r, rSize, nil
 }
 
 // UnreadRune implements the io.RuneScanner interface.
+//
+// By default, UnreadRune moves the offset back by the length of the rune that
+// precedes it, regardless of the preceding operation. If strict unreading is
+// enabled (see SetStrictUnread), UnreadRune instead returns an error unless
+// the preceding operation on f was a ReadRune, as for bufio.Reader and
+// bytes.Buffer, and moves the offset back by the length of the rune it read.
 func (f *File) UnreadRune() error {
+	if f.strictUnread {
+		if !f.canUnread() || f.lastRead == opReadByte {
+			return errors.New("UnreadRune: previous operation was not a successful ReadRune")
+		}
+		f.offset -= int64(f.lastRead)
+		f.lastRead = opInvalid
+		return nil
+	}
 	if f.offset == 0 {
 		return errors.New("UnreadRune: no runes to unread")
 	}
@@ -219,12 +776,47 @@ func (f *File) UnreadRune() error {
 	return nil
 }
 
+// SetStrictUnread sets whether UnreadByte and UnreadRune require that the
+// preceding operation was a corresponding read, as specified by the
+// io.ByteScanner and io.RuneScanner interfaces and implemented by bufio.Reader
+// and bytes.Buffer. Strict unreading is disabled by default.
+//
+// With strict unreading enabled, a File can be passed safely to parsers that
+// rely on the standard library's contract: UnreadByte and UnreadRune return an
+// error if any other operation (such as Read, Write, or Seek) has changed or set
+// the offset since the last ReadByte or ReadRune, and at most one byte or rune
+// can be unread after each read.
+func (f *File) SetStrictUnread(strict bool) {
+	f.strictUnread = strict
+	f.lastRead = opInvalid
+}
+
+// canUnread reports whether the preceding operation on f was a ReadByte or
+// ReadRune that has not since been undone.
+func (f *File) canUnread() bool {
+	return f.lastRead != opInvalid && f.offset == f.lastReadEnd
+}
+
 // ReadAt implements the io.ReaderAt interface.
+//
+// As with os.File, ReadAt at or beyond the end of the File's data returns 0 and
+// io.EOF, even if off is within the capacity of the backing slice: bytes beyond
+// the size have not been written. (To read such bytes as zeroes, use
+// ReadAtSparse.)
+//
+// ReadAt may be called concurrently with other calls to ReadAt and WriteAt.
+// It observes the contents of the File either before or after any concurrent
+// WriteAt that grows the File, but does not copy from a stale backing slice.
+// (As with os.File, a ReadAt that overlaps the region written by a concurrent
+// WriteAt may observe some, all, or none of the written bytes.)
 func (f *File) ReadAt(b []byte, off int64) (n int, err error) {
 	if off < 0 {
 		return 0, errors.New("ReadAt: invalid offset")
 	}
 
+	f.writeAtMu.RLock()
+	defer f.writeAtMu.RUnlock()
+
 	size := f.Size()
 	if off >= size {
 		return 0, io.EOF
@@ -236,6 +828,38 @@ func (f *File) ReadAt(b []byte, off int64) (n int, err error) {
 	return n, nil
 }
 
+// ReadAtSparse is like ReadAt, but treats the File as if it were extended with
+// zero bytes up to the given logical size, like a sparse os.File whose
+// trailing hole has not yet been written. If size is smaller than the File's
+// actual size, the actual size is used instead.
+//
+// Like ReadAt, ReadAtSparse may be called concurrently with WriteAt.
+func (f *File) ReadAtSparse(b []byte, off, size int64) (n int, err error) {
+	if off < 0 {
+		return 0, errors.New("ReadAtSparse: invalid offset")
+	}
+
+	f.writeAtMu.RLock()
+	defer f.writeAtMu.RUnlock()
+
+	if s := f.Size(); size < s {
+		size = s
+	}
+	if off >= size {
+		return 0, io.EOF
+	}
+	if int64(len(b)) > size-off {
+		b = b[:size-off]
+		err = io.EOF
+	}
+
+	n = copy(b, f.dataFrom(off))
+	for i := range b[n:] {
+		b[n+i] = 0
+	}
+	return len(b), err
+}
+
 // ReadBytes reads until the next occurrence of delim in the input,
 // returning a copy of the data up to and including the delimiter.
 // If ReadBytes encounters the end of the file before finding the delimiter,
@@ -262,6 +886,21 @@ func (f *File) ReadString(delim byte) (line string, err error) {
 	return string(slice), err
 }
 
+// ReadLine reads until the next '\n' in the input, returning a copy of the
+// data up to but not including the line ending ("\n" or "\r\n").
+// If ReadLine encounters the end of the file before finding a '\n',
+// it returns the data read before the error and io.EOF.
+func (f *File) ReadLine() (line []byte, err error) {
+	slice, err := f.readSlice('\n')
+	if err == nil {
+		slice = slice[:len(slice)-1]
+		if len(slice) > 0 && slice[len(slice)-1] == '\r' {
+			slice = slice[:len(slice)-1]
+		}
+	}
+	return append([]byte(nil), slice...), err
+}
+
 func (f *File) readSlice(delim byte) (line []byte, err error) {
 	buf := f.next()
 	i := bytes.IndexByte(buf, delim)
@@ -271,23 +910,55 @@ func (f *File) readSlice(delim byte) (line []byte, err error) {
 		err = io.EOF
 	}
 	f.offset += int64(len(buf))
-	return line, err
+	return buf, err
 }
 
 // WriteTo implements the io.WriterTo interface.
+//
+// If w accepts only part of the data without returning an error, WriteTo
+// retries with the remaining data, returning io.ErrShortWrite only if a Write
+// makes no progress.
 func (f *File) WriteTo(w io.Writer) (n int64, err error) {
+	return f.writeOut(w, f.next())
+}
+
+// WriteToN is like WriteTo, but writes at most n bytes from the File.
+// If fewer than n bytes remain after the current offset, WriteToN writes those
+// bytes and returns io.EOF.
+func (f *File) WriteToN(w io.Writer, n int64) (written int64, err error) {
+	if n < 0 {
+		return 0, errors.New("WriteToN: negative count")
+	}
 	b := f.next()
-	if len(b) == 0 {
-		return 0, nil
+	if int64(len(b)) > n {
+		b = b[:n]
 	}
+	written, err = f.writeOut(w, b)
+	if written < n && err == nil {
+		return written, io.EOF
+	}
+	return written, err
+}
 
-	dn, err := w.Write(b)
-	n = int64(dn)
-	f.offset += n
-	if n < int64(len(b)) && err == nil {
-		return n, io.ErrShortWrite
+// writeOut writes b, which must be the prefix of f.next(), to w, advancing the
+// offset by the number of bytes written.
+func (f *File) writeOut(w io.Writer, b []byte) (n int64, err error) {
+	for len(b) > 0 {
+		dn, err := w.Write(b)
+		if dn < 0 || dn > len(b) {
+			return n, errInvalidWrite
+		}
+		n += int64(dn)
+		f.offset += int64(dn)
+		if err != nil {
+			return n, err
+		}
+		if dn == 0 {
+			return n, io.ErrShortWrite
+		}
+		b = b[dn:]
 	}
-	return n, err
+	return n, nil
 }
 
 // Seek sets the offset for the next Read or Write to offset, interpreted
@@ -328,21 +999,80 @@ func (f *File) Seek(offset int64, whence int) (ret int64, err error) {
 	}
 
 	f.offset = abs
+	f.lastRead = opInvalid
 	return f.offset, nil
 }
 
+// Compact discards the data before the current offset, moving the remaining
+// data to the start of the backing slice and resetting the offset to 0.
+// (For a read-only File, Compact instead discards the data by reslicing.)
+// Compact allows a File to be used as a queue, with data written at the end and
+// read from the start.
+//
+// If f is not fixed and the remaining data occupies only a small fraction of
+// the backing slice, Compact may also reallocate a smaller backing slice.
+func (f *File) Compact() {
+	if f.readOnly {
+		f.buf = f.next()
+		f.offset = 0
+		return
+	}
+	f.unshare()
+	rest := f.next()
+	if !f.fixed && cap(f.buf) > minRead && len(rest) <= cap(f.buf)/4 {
+		f.buf = append(make([]byte, 0, 2*len(rest)), rest...)
+	} else {
+		f.buf = f.buf[:copy(f.buf, rest)]
+	}
+	f.offset = 0
+}
+
+// Offset returns the current read/write offset of the File.
+// It is equivalent to Seek(0, io.SeekCurrent), but cannot fail.
+func (f *File) Offset() int64 {
+	return f.offset
+}
+
+// SetOffset sets the offset for the next Read or Write to off, relative to the
+// start of the File. It is equivalent to Seek(off, io.SeekStart).
+func (f *File) SetOffset(off int64) error {
+	if off < 0 {
+		return errors.New("SetOffset: invalid offset")
+	}
+	f.offset = off
+	f.lastRead = opInvalid
+	return nil
+}
+
+// Rewind resets the offset for the next Read or Write to the start of the File.
+// It is equivalent to Seek(0, io.SeekStart), but cannot fail.
+func (f *File) Rewind() {
+	f.offset = 0
+	f.lastRead = opInvalid
+}
+
+// Tell returns the current read/write offset of the File.
+// It is a synonym for Offset, for familiarity to users of C's stdio.
+func (f *File) Tell() int64 {
+	return f.offset
+}
+
 // Truncate changes the size of the File.
 // It does not change the offset or allocate a new backing slice.
 //
 // If the indicated size is larger than f's size limit,
 // Truncate returns ErrFileSizeLimit and leaves the size unchanged.
 func (f *File) Truncate(size int64) error {
+	if f.readOnly {
+		return ErrReadOnly
+	}
 	if size < 0 {
 		return errors.New("Truncate: negative size")
 	}
 	if size > f.SizeLimit() {
 		return ErrFileSizeLimit
 	}
+	f.unshare()
 	if growth := int(size) - len(f.buf); growth > 0 {
 		// To provide the same semantics as os.File.Truncate, sero-fill the trailing
 		// bytes of f.buf even if we don't have to reallocate it.
@@ -352,6 +1082,23 @@ func (f *File) Truncate(size int64) error {
 	return nil
 }
 
+// TruncateAndShrink is like Truncate, but if f is not fixed and the new size
+// is only a small fraction of the capacity of the backing slice, it also
+// reallocates a backing slice of exactly the new size, so that the memory for
+// the old one can be reclaimed.
+//
+// Plain Truncate never reallocates, which is more efficient if the File will
+// grow again.
+func (f *File) TruncateAndShrink(size int64) error {
+	if err := f.Truncate(size); err != nil {
+		return err
+	}
+	if !f.fixed && cap(f.buf) > minRead && len(f.buf) <= cap(f.buf)/4 {
+		f.buf = append(make([]byte, 0, len(f.buf)), f.buf...)
+	}
+	return nil
+}
+
 // Write writes len(b) bytes to the File.
 //
 // If the new offset is higher than the previous size of the File
@@ -361,6 +1108,18 @@ func (f *File) Truncate(size int64) error {
 // offset to be equal to the limit and writes as many bytes as will fit, and
 // returns the number of bytes actually written along with ErrFileSizeLimit.
 func (f *File) Write(b []byte) (n int, err error) {
+	if f.readOnly {
+		return 0, ErrReadOnly
+	}
+	if limit := int(f.SizeLimit()); f.ring && len(b) > limit {
+		// Only the trailing bytes of b will be retained.
+		if _, err := f.Write(b[len(b)-limit:]); err != nil {
+			return 0, err
+		}
+		return len(b), nil
+	}
+	f.makeRoom(len(b))
+
 	buf, err := f.growAt(f.offset, 0, len(b))
 	if err != nil {
 		return 0, err
@@ -375,6 +1134,10 @@ func (f *File) Write(b []byte) (n int, err error) {
 
 // WriteByte implements the io.ByteWriter interface.
 func (f *File) WriteByte(c byte) error {
+	if f.readOnly {
+		return ErrReadOnly
+	}
+	f.makeRoom(1)
 	buf, err := f.growAt(f.offset, 1, 1)
 	if err != nil {
 		return err
@@ -389,8 +1152,12 @@ func (f *File) WriteByte(c byte) error {
 
 // WriteRune implements the io.RuneWriter interface.
 func (f *File) WriteRune(r rune) (n int, err error) {
+	if f.readOnly {
+		return 0, ErrReadOnly
+	}
 	var arr [utf8.UTFMax]byte
 	n = utf8.EncodeRune(arr[:], r)
+	f.makeRoom(n)
 	buf, err := f.growAt(f.offset, n, n)
 	if err != nil {
 		return 0, err
@@ -403,6 +1170,18 @@ func (f *File) WriteRune(r rune) (n int, err error) {
 // WriteString is like Write, but writes the contents of string s rather than a
 // slice of bytes.
 func (f *File) WriteString(s string) (n int, err error) {
+	if f.readOnly {
+		return 0, ErrReadOnly
+	}
+	if limit := int(f.SizeLimit()); f.ring && len(s) > limit {
+		// Only the trailing bytes of s will be retained.
+		if _, err := f.WriteString(s[len(s)-limit:]); err != nil {
+			return 0, err
+		}
+		return len(s), nil
+	}
+	f.makeRoom(len(s))
+
 	buf, err := f.growAt(f.offset, 0, len(s))
 	if err != nil {
 		return 0, err
@@ -415,6 +1194,213 @@ func (f *File) WriteString(s string) (n int, err error) {
 	return n, nil
 }
 
+// AppendFrom appends the unread contents of g (from g's offset to its size)
+// to the end of f, advancing g's offset past the bytes appended. It does not
+// change f's offset. f and g may be the same File.
+//
+// If appending all of g's unread contents would exceed f's size limit (for
+// example, if f is fixed), AppendFrom appends as many bytes as will fit and
+// returns the number of bytes appended along with ErrFileSizeLimit.
+// AppendFrom does not discard data from a ring File.
+func (f *File) AppendFrom(g *File) (n int64, err error) {
+	if f.readOnly {
+		return 0, ErrReadOnly
+	}
+	b := g.next()
+	buf, err := f.growAt(f.Size(), 0, len(b))
+	if err != nil {
+		return 0, err
+	}
+	m := copy(buf, b)
+	g.offset += int64(m)
+	if m < len(b) {
+		return int64(m), ErrFileSizeLimit
+	}
+	return int64(m), nil
+}
+
+// Insert inserts the contents of b into the File at offset off, shifting the
+// existing data at and after off toward the end of the File. The size of the
+// File increases by len(b), and if the current offset is at or after off it is
+// advanced by len(b) so that it continues to refer to the same byte of data.
+//
+// Insert requires time proportional to the size of the File.
+//
+// If the new size would exceed f's size limit, Insert returns
+// ErrFileSizeLimit and leaves the File unchanged.
+func (f *File) Insert(off int64, b []byte) error {
+	if f.readOnly {
+		return ErrReadOnly
+	}
+	size := f.Size()
+	if off < 0 || off > size {
+		return errors.New("Insert: invalid offset")
+	}
+	if _, err := f.growAt(size, len(b), len(b)); err != nil {
+		return err
+	}
+	copy(f.buf[off+int64(len(b)):], f.buf[off:size])
+	copy(f.buf[off:], b)
+	if f.offset >= off {
+		f.offset += int64(len(b))
+	}
+	return nil
+}
+
+// Delete removes n bytes from the File starting at offset off, shifting the
+// remaining data after them toward the start of the File. The size of the File
+// decreases by n. If the current offset is after the deleted range it is
+// decreased by n, and if it is within the deleted range it is set to off.
+//
+// Delete requires time proportional to the size of the File.
+func (f *File) Delete(off, n int64) error {
+	if f.readOnly {
+		return ErrReadOnly
+	}
+	size := f.Size()
+	if off < 0 || off > size {
+		return errors.New("Delete: invalid offset")
+	}
+	if n < 0 || n > size-off {
+		return errors.New("Delete: invalid count")
+	}
+	f.unshare()
+	copy(f.buf[off:], f.buf[off+n:size])
+	f.buf = f.buf[:size-n]
+	if f.offset >= off+n {
+		f.offset -= n
+	} else if f.offset > off {
+		f.offset = off
+	}
+	return nil
+}
+
+// minRead is the minimum number of bytes that ReadFrom attempts to read in a
+// single call to Read, mirroring the constant of the same name in package bytes.
+const minRead = 512
+
+// ReadFrom implements the io.ReaderFrom interface.
+//
+// ReadFrom reads data from r until io.EOF, writing it to the File starting at
+// the current offset and advancing the offset by the number of bytes read.
+// The return value n is the number of bytes read. Any error except io.EOF
+// encountered during the read is also returned.
+//
+// If the data from r would cause the File to exceed its size limit, ReadFrom
+// fills the File up to the limit and returns ErrFileSizeLimit. A File whose
+// data ends exactly at its size limit must read one more byte from r in order
+// to distinguish that case from io.EOF.
+func (f *File) ReadFrom(r io.Reader) (n int64, err error) {
+	if f.readOnly {
+		return 0, ErrReadOnly
+	}
+	f.unshare()
+	if f.offset > f.Size() {
+		// Zero-fill the gap between the end of the data and the offset,
+		// as Write would.
+		if err := f.Truncate(f.offset); err != nil {
+			return 0, err
+		}
+	}
+
+	var (
+		limit   = f.SizeLimit()
+		scratch []byte
+	)
+	for {
+		if f.offset >= limit && f.ring && limit > 0 {
+			// The ring is full. Read into a scratch buffer and let Write discard
+			// only as much old data as is actually needed to make room.
+			if scratch == nil {
+				size := int64(minRead)
+				if size > limit {
+					size = limit
+				}
+				scratch = make([]byte, size)
+			}
+			m, err := r.Read(scratch)
+			f.Write(scratch[:m])
+			n += int64(m)
+			if err == io.EOF {
+				return n, nil
+			}
+			if err != nil {
+				return n, err
+			}
+			continue
+		}
+
+		if f.offset >= limit {
+			// The File is full. Check whether r has any more data to offer.
+			var b [1]byte
+			m, err := r.Read(b[:])
+			if m > 0 {
+				return n, ErrFileSizeLimit
+			}
+			if err == io.EOF {
+				return n, nil
+			}
+			if err != nil {
+				return n, err
+			}
+			continue
+		}
+
+		want := f.offset + minRead
+		if want > limit {
+			want = limit
+		}
+		f.reserve(want)
+
+		end := int64(cap(f.buf))
+		if end > limit {
+			end = limit
+		}
+		m, err := r.Read(f.buf[f.offset:end])
+		if end := f.offset + int64(m); end > f.Size() {
+			f.buf = f.buf[:end]
+		}
+		f.offset += int64(m)
+		n += int64(m)
+
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+	}
+}
+
+// Fill writes n copies of the byte c to the File at the current offset,
+// as if by Write but without allocating a temporary slice.
+//
+// If the new size would exceed f's size limit, Fill writes as many bytes as
+// will fit and returns the number of bytes actually written along with
+// ErrFileSizeLimit.
+func (f *File) Fill(c byte, n int) (int, error) {
+	if f.readOnly {
+		return 0, ErrReadOnly
+	}
+	if n < 0 {
+		return 0, errors.New("Fill: negative count")
+	}
+	f.makeRoom(n)
+
+	buf, err := f.growAt(f.offset, 0, n)
+	if err != nil {
+		return 0, err
+	}
+	for i := range buf {
+		buf[i] = c
+	}
+	f.offset += int64(len(buf))
+	if len(buf) < n {
+		return len(buf), ErrFileSizeLimit
+	}
+	return n, nil
+}
+
 // WriteAt writes len(b) bytes to the File at the indicated offset.
 //
 // If the highest offset to be written is higher than the current size of the
@@ -425,6 +1411,20 @@ func (f *File) WriteString(s string) (n int, err error) {
 // that do fit within the limit and returns the number of bytes written along
 // with ErrFileSizeLimit.
 func (f *File) WriteAt(b []byte, offset int64) (n int, err error) {
+	n, _, err = f.WriteAtReport(b, offset)
+	return n, err
+}
+
+// WriteAtReport is like WriteAt, but also reports the number of bytes by which
+// the write increased the size of the File.
+//
+// Because the size is measured while the write holds its lock on the File's
+// size, the reported growth is accurate even if other calls to WriteAt or
+// WriteAtReport are in progress concurrently.
+func (f *File) WriteAtReport(b []byte, offset int64) (n int, grew int64, err error) {
+	if f.readOnly {
+		return 0, 0, ErrReadOnly
+	}
 	n = len(b)
 
 	// os.File.WriteAt implicitly grows the file to the maximum offset written.
@@ -433,18 +1433,22 @@ func (f *File) WriteAt(b []byte, offset int64) (n int, err error) {
 	// So we at least need to lock the File enough to prevent a new buffer from
 	// being allocated while the old one is still being written to.
 	f.writeAtMu.RLock()
-	if int64(len(f.buf)-n) < offset {
+	if f.shared || int64(len(f.buf)-n) < offset {
 		f.writeAtMu.RUnlock()
 		f.writeAtMu.Lock()
+		// growAt also makes a private copy of f.buf if it is shared.
+		//
 		// When we drop the write-lock, f.buf may grow again (invalidating
 		// references to the buffer) before we can reacquire a read-lock.
 		// Record only the limit on the number of bytes to be written.
+		size := f.Size()
 		buf, err := f.growAt(offset, 0, len(b))
 		n = len(buf)
+		grew = f.Size() - size
 		f.writeAtMu.Unlock()
 
 		if err != nil {
-			return 0, err
+			return 0, 0, err
 		}
 		f.writeAtMu.RLock()
 	}
@@ -452,9 +1456,92 @@ func (f *File) WriteAt(b []byte, offset int64) (n int, err error) {
 	f.writeAtMu.RUnlock()
 
 	if n < len(b) {
-		return 0, ErrFileSizeLimit
+		return n, grew, ErrFileSizeLimit
 	}
-	return n, err
+	return n, grew, nil
+}
+
+// readFromAtChunk is the size of the scratch buffer used by ReadFromAt.
+const readFromAtChunk = 32 << 10
+
+// ReadFromAt reads data from r until io.EOF and writes it to the File starting
+// at offset off, as if by successive calls to WriteAt. It returns the number of
+// bytes written to the File. Any error except io.EOF encountered during the
+// read is also returned.
+//
+// ReadFromAt does not use or change the current offset, and like WriteAt it may
+// be called concurrently with other calls to ReadFromAt, WriteAt, and ReadAt.
+// Because r is read without holding any lock on the File, a slow r does not
+// block those concurrent calls.
+//
+// If the data from r would cause the File to exceed its size limit,
+// ReadFromAt writes as many bytes as fit within the limit and returns
+// ErrFileSizeLimit.
+func (f *File) ReadFromAt(r io.Reader, off int64) (n int64, err error) {
+	if f.readOnly {
+		return 0, ErrReadOnly
+	}
+	if off < 0 {
+		return 0, errors.New("ReadFromAt: invalid offset")
+	}
+
+	buf := make([]byte, readFromAtChunk)
+	for {
+		m, rerr := r.Read(buf)
+		if m > 0 {
+			w, werr := f.WriteAt(buf[:m], off+n)
+			n += int64(w)
+			if werr != nil {
+				return n, werr
+			}
+		}
+		if rerr == io.EOF {
+			return n, nil
+		}
+		if rerr != nil {
+			return n, rerr
+		}
+	}
+}
+
+// makeRoom discards the oldest data from a ring File, if needed, so that n
+// bytes can be written at the current offset without exceeding its size limit.
+// If f is not a ring File, makeRoom is a no-op.
+func (f *File) makeRoom(n int) {
+	if !f.ring {
+		return
+	}
+	f.unshare()
+	limit := f.SizeLimit()
+	excess := f.offset + int64(n) - limit
+	if excess <= 0 || int64(n) > limit {
+		return
+	}
+
+	size := int(f.Size())
+	discard := int(excess)
+	if discard > size {
+		discard = size
+	}
+	f.offset -= excess
+
+	// Moving the retained data to the start of the backing slice on every write
+	// would make a stream of small writes to a full ring quadratic. Instead,
+	// advance buf as a window over an array of twice its capacity, and move the
+	// data back to the start of that array only when the window reaches the end.
+	c := cap(f.buf)
+	if f.ringBase != nil {
+		if head := f.ringHead + discard; head+c <= len(f.ringBase) {
+			f.ringHead = head
+			f.buf = f.ringBase[head : head+size-discard : head+c]
+			return
+		}
+	} else {
+		f.ringBase = make([]byte, 2*c)
+	}
+	copy(f.ringBase, f.buf[discard:size])
+	f.ringHead = 0
+	f.buf = f.ringBase[: size-discard : c]
 }
 
 // growAt grows f's backing array so that it can hold up to maxN bytes,
@@ -462,6 +1549,7 @@ func (f *File) WriteAt(b []byte, offset int64) (n int, err error) {
 //
 // growAt returns the subslice of up to maxN bytes beginning at offset.
 func (f *File) growAt(offset int64, minN, maxN int) (buf []byte, err error) {
+	f.unshare()
 	if int64(len(f.buf))-offset >= int64(maxN) {
 		return f.buf[offset:][:maxN], nil
 	}
@@ -476,9 +1564,47 @@ func (f *File) growAt(offset int64, minN, maxN int) (buf []byte, err error) {
 
 	size := int(offset + n)
 	if cap(f.buf) >= size {
+		// The backing slice may contain stale data beyond the current size
+		// (for example, from before a Truncate). Zero-fill any gap between the
+		// old size and offset so that it reads as zeroes, as for a sparse
+		// os.File. (The caller overwrites the bytes from offset onward, which
+		// may alias the data to be written, as for AvailableBuffer.)
+		old := int64(len(f.buf))
 		f.buf = f.buf[:size]
+		if offset > old {
+			zero(f.buf[old:offset])
+		}
 	} else {
 		f.buf = append(f.buf, make([]byte, size-len(f.buf))...)
 	}
 	return f.buf[offset:size], nil
 }
+
+// zero sets every byte of b to 0.
+func zero(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
+
+// reserve reallocates f's backing slice, if needed, so that its capacity is at
+// least size, without changing its length.
+//
+// The caller must ensure that size does not exceed f's size limit.
+func (f *File) reserve(size int64) {
+	if int64(cap(f.buf)) >= size {
+		return
+	}
+
+	newCap := 2 * int64(cap(f.buf))
+	if newCap < size {
+		newCap = size
+	}
+	if limit := f.SizeLimit(); newCap > limit {
+		newCap = limit
+	}
+	buf := make([]byte, len(f.buf), newCap)
+	copy(buf, f.buf)
+	f.buf = buf
+	f.shared = false
+}
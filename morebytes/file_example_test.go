@@ -7,6 +7,7 @@ package morebytes_test
 import (
 	"fmt"
 	"io"
+	"strconv"
 	"sync"
 
 	"github.com/bcmills/more/morebytes"
@@ -87,3 +88,35 @@ func ExampleFile_WriteAt() {
 	// ""
 	// "Hello, world!"
 }
+
+func ExampleFile_Commit() {
+	// AvailableBuffer and Commit can be used to format directly into the
+	// spare capacity of a File, without an intermediate copy.
+
+	w := morebytes.NewFixedFile(make([]byte, 0, 16))
+	w.WriteString("n = ")
+	b := strconv.AppendInt(w.AvailableBuffer(), 42, 10)
+	w.Commit(len(b))
+	fmt.Printf("%q\n", w.Bytes())
+
+	// Output:
+	// "n = 42"
+}
+
+func ExampleNewRingFile() {
+	// A ring File retains only the most recent data written to it,
+	// which is useful for keeping the tail of a log.
+
+	w := morebytes.NewRingFile(make([]byte, 0, 8))
+	for i := 1; i <= 5; i++ {
+		fmt.Fprintf(w, "%d,", i)
+	}
+	fmt.Printf("%q\n", w.Bytes())
+
+	w.WriteString("and many more")
+	fmt.Printf("%q\n", w.Bytes())
+
+	// Output:
+	// "2,3,4,5,"
+	// "any more"
+}
@@ -0,0 +1,1487 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package morebytes_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"testing/iotest"
+
+	"github.com/bcmills/more/morebytes"
+)
+
+func TestFileRemaining(t *testing.T) {
+	f := morebytes.NewFile([]byte("Hello, world!"))
+	if n := f.Remaining(); n != 13 {
+		t.Fatalf("Remaining() = %v; want 13", n)
+	}
+
+	f.Next(7)
+	if n := f.Remaining(); n != 6 {
+		t.Fatalf("after Next(7): Remaining() = %v; want 6", n)
+	}
+
+	f.Seek(100, io.SeekStart)
+	if n := f.Remaining(); n != 0 {
+		t.Fatalf("after Seek(100, io.SeekStart): Remaining() = %v; want 0", n)
+	}
+
+	f.Seek(0, io.SeekEnd)
+	f.WriteString(" Goodbye!")
+	f.Seek(-4, io.SeekCurrent)
+	if n := f.Remaining(); n != 4 {
+		t.Fatalf("after growing Write: Remaining() = %v; want 4", n)
+	}
+}
+
+func TestFileReadFrom(t *testing.T) {
+	in := strings.Repeat("Hello, world! ", 100)
+
+	f := morebytes.NewFile([]byte("Greetings!"))
+	f.Seek(0, io.SeekEnd)
+	n, err := io.Copy(f, strings.NewReader(in))
+	if n != int64(len(in)) || err != nil {
+		t.Fatalf("io.Copy(f, _) = %v, %v; want %v, <nil>", n, err, len(in))
+	}
+	if want := "Greetings!" + in; f.String() != want {
+		t.Fatalf("contents = %q; want %q", f.String(), want)
+	}
+	if off, _ := f.Seek(0, io.SeekCurrent); off != f.Size() {
+		t.Fatalf("offset = %v; want %v", off, f.Size())
+	}
+}
+
+func TestFixedFileReadFrom(t *testing.T) {
+	for _, tc := range []struct {
+		in      string
+		wantN   int64
+		wantErr error
+	}{
+		{in: "Hello", wantN: 5},
+		{in: "Hello, world!", wantN: 13},
+		{in: "Hello, world!!", wantN: 13, wantErr: morebytes.ErrFileSizeLimit},
+	} {
+		f := morebytes.NewFixedFile(make([]byte, 0, 13))
+		n, err := f.ReadFrom(strings.NewReader(tc.in))
+		if n != tc.wantN || err != tc.wantErr {
+			t.Errorf("ReadFrom(%q) = %v, %v; want %v, %v", tc.in, n, err, tc.wantN, tc.wantErr)
+		}
+		if want := tc.in[:tc.wantN]; f.String() != want {
+			t.Errorf("after ReadFrom(%q): contents = %q; want %q", tc.in, f.String(), want)
+		}
+	}
+}
+
+func TestFilePeek(t *testing.T) {
+	f := morebytes.NewFile([]byte("Hello, world!"))
+	f.Next(7)
+
+	b, err := f.Peek(5)
+	if string(b) != "world" || err != nil {
+		t.Fatalf(`Peek(5) = %q, %v; want "world", <nil>`, b, err)
+	}
+	if n := f.Remaining(); n != 6 {
+		t.Fatalf("after Peek: Remaining() = %v; want 6", n)
+	}
+
+	b, err = f.Peek(10)
+	if string(b) != "world!" || err != io.EOF {
+		t.Fatalf(`Peek(10) = %q, %v; want "world!", EOF`, b, err)
+	}
+}
+
+func TestFileReserve(t *testing.T) {
+	f := morebytes.NewFile([]byte("Hello"))
+	f.Next(2)
+	if err := f.Reserve(100); err != nil {
+		t.Fatalf("Reserve(100) = %v", err)
+	}
+	if c := f.Cap(); c < 105 {
+		t.Errorf("after Reserve(100): Cap() = %v; want at least 105", c)
+	}
+	if s := f.Size(); s != 5 {
+		t.Errorf("after Reserve(100): Size() = %v; want 5", s)
+	}
+	if n := f.Remaining(); n != 3 {
+		t.Errorf("after Reserve(100): Remaining() = %v; want 3", n)
+	}
+
+	ff := morebytes.NewFixedFile(make([]byte, 5, 10))
+	if err := ff.Reserve(5); err != nil {
+		t.Errorf("fixed: Reserve(5) = %v; want <nil>", err)
+	}
+	if err := ff.Reserve(6); err != morebytes.ErrFileSizeLimit {
+		t.Errorf("fixed: Reserve(6) = %v; want ErrFileSizeLimit", err)
+	}
+	if c := ff.Cap(); c != 10 {
+		t.Errorf("fixed: Cap() = %v; want 10", c)
+	}
+}
+
+func TestFileInsertDelete(t *testing.T) {
+	f := morebytes.NewFile([]byte("Hello, world!"))
+	f.Seek(7, io.SeekStart)
+
+	if err := f.Insert(5, []byte(" there")); err != nil {
+		t.Fatalf(`Insert(5, " there") = %v`, err)
+	}
+	if want := "Hello there, world!"; f.String() != want {
+		t.Fatalf("after Insert: contents = %q; want %q", f.String(), want)
+	}
+	if c, _ := f.ReadByte(); c != 'w' {
+		t.Fatalf("after Insert: ReadByte() = %q; want 'w'", c)
+	}
+	f.UnreadByte()
+
+	if err := f.Delete(0, 12); err != nil {
+		t.Fatalf("Delete(0, 12) = %v", err)
+	}
+	if want := " world!"; f.String() != want {
+		t.Fatalf("after Delete: contents = %q; want %q", f.String(), want)
+	}
+	if c, _ := f.ReadByte(); c != 'w' {
+		t.Fatalf("after Delete: ReadByte() = %q; want 'w'", c)
+	}
+
+	if err := f.Delete(3, 10); err == nil {
+		t.Fatalf("Delete(3, 10) unexpectedly succeeded")
+	}
+
+	ff := morebytes.NewFixedFile(make([]byte, 5, 8))
+	if err := ff.Insert(0, []byte("1234")); err != morebytes.ErrFileSizeLimit {
+		t.Fatalf("fixed: Insert(0, <4 bytes>) = %v; want ErrFileSizeLimit", err)
+	}
+	if s := ff.Size(); s != 5 {
+		t.Fatalf("fixed: after failed Insert, Size() = %v; want 5", s)
+	}
+}
+
+func TestFileClone(t *testing.T) {
+	b := make([]byte, 0, 10)
+	f := morebytes.NewFixedFile(b)
+	f.WriteString("Hello")
+
+	c := f.Clone()
+	c.WriteString(", world")
+	if err := c.WriteByte('!'); err != morebytes.ErrFileSizeLimit {
+		t.Errorf("clone: WriteByte past limit = %v; want ErrFileSizeLimit", err)
+	}
+
+	if want := "Hello, wor"; c.String() != want {
+		t.Errorf("clone contents = %q; want %q", c.String(), want)
+	}
+	if want := "Hello"; f.String() != want {
+		t.Errorf("original contents = %q; want %q", f.String(), want)
+	}
+
+	// A clone of a read-only File is writable, and independent of the original.
+	ro := f.ReadOnly()
+	rc := ro.Clone()
+	if _, err := rc.WriteAt([]byte("J"), 0); err != nil {
+		t.Errorf("clone of read-only File: WriteAt = %v; want <nil>", err)
+	}
+	if want := "Jello"; rc.String() != want {
+		t.Errorf("clone of read-only File: contents = %q; want %q", rc.String(), want)
+	}
+	if want := "Hello"; ro.String() != want || f.String() != want {
+		t.Errorf("after writing clone: contents %q, %q; want %q", ro.String(), f.String(), want)
+	}
+}
+
+func TestFileBinaryMarshaling(t *testing.T) {
+	f := morebytes.NewFile([]byte("Hello, world!"))
+	f.Next(5)
+
+	b, err := f.MarshalBinary()
+	if string(b) != "Hello, world!" || err != nil {
+		t.Fatalf(`MarshalBinary() = %q, %v; want "Hello, world!", <nil>`, b, err)
+	}
+
+	var g morebytes.File
+	if err := g.UnmarshalBinary(b); err != nil {
+		t.Fatalf("UnmarshalBinary(_) = %v", err)
+	}
+	b[0] = 'J'
+	if want := "Hello, world!"; g.String() != want {
+		t.Fatalf("after UnmarshalBinary: contents = %q; want %q", g.String(), want)
+	}
+	if n := g.Remaining(); n != g.Size() {
+		t.Fatalf("after UnmarshalBinary: Remaining() = %v; want %v", n, g.Size())
+	}
+
+	ff := morebytes.NewFixedFile(make([]byte, 0, 5))
+	if err := ff.UnmarshalBinary(b); err != morebytes.ErrFileSizeLimit {
+		t.Fatalf("fixed: UnmarshalBinary(<13 bytes>) = %v; want ErrFileSizeLimit", err)
+	}
+	if err := ff.UnmarshalBinary(b[:5]); err != nil || ff.String() != "Jello" {
+		t.Fatalf(`fixed: UnmarshalBinary(<5 bytes>) = %v, contents %q; want <nil>, "Jello"`, err, ff.String())
+	}
+}
+
+func TestRingFile(t *testing.T) {
+	f := morebytes.NewRingFile(make([]byte, 0, 6))
+	f.WriteString("Hello")
+	f.WriteString(", world!")
+	if want := "world!"; f.String() != want {
+		t.Fatalf("contents = %q; want %q", f.String(), want)
+	}
+
+	f.Seek(0, io.SeekStart)
+	b, err := io.ReadAll(f)
+	if string(b) != "world!" || err != nil {
+		t.Fatalf(`ReadAll(f) = %q, %v; want "world!", <nil>`, b, err)
+	}
+
+	f.WriteByte('?')
+	f.WriteRune('¡')
+	if want := "ld!?¡"; f.String() != want {
+		t.Fatalf("contents = %q; want %q", f.String(), want)
+	}
+
+	n, err := f.ReadFrom(strings.NewReader(strings.Repeat("x", 1000) + "abc"))
+	if n != 1003 || err != nil {
+		t.Fatalf("ReadFrom(<1003 bytes>) = %v, %v; want 1003, <nil>", n, err)
+	}
+	if want := "xxxabc"; f.String() != want {
+		t.Fatalf("contents = %q; want %q", f.String(), want)
+	}
+}
+
+func TestRingFileManyWrites(t *testing.T) {
+	// Write enough small chunks to wrap the ring many times, checking the
+	// contents against a simple model along the way.
+	const size = 10
+	f := morebytes.NewRingFile(make([]byte, 0, size))
+	var all []byte
+	for i := 0; i < 100; i++ {
+		chunk := strconv.Itoa(i)
+		f.WriteString(chunk)
+		all = append(all, chunk...)
+
+		want := all
+		if len(want) > size {
+			want = want[len(want)-size:]
+		}
+		if f.String() != string(want) {
+			t.Fatalf("after writing %q: contents = %q; want %q", all, f.String(), want)
+		}
+		if f.Offset() != int64(len(want)) || f.Cap() != size {
+			t.Fatalf("after writing %q: Offset() = %v, Cap() = %v; want %v, %v", all, f.Offset(), f.Cap(), len(want), size)
+		}
+	}
+
+	// Writes that do not append still operate within the retained data.
+	f.WriteAt([]byte("!"), 0)
+	f.Seek(0, io.SeekEnd)
+	f.WriteByte('?')
+	if want := "596979899?"; f.String() != want {
+		t.Fatalf("contents = %q; want %q", f.String(), want)
+	}
+}
+
+func BenchmarkRingFileSmallWrites(b *testing.B) {
+	const size = 64 << 10
+	f := morebytes.NewRingFile(make([]byte, 0, size))
+	f.Fill('x', size)
+	chunk := []byte("0123456789abcdef")
+
+	b.SetBytes(int64(len(chunk)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		f.Write(chunk)
+	}
+}
+
+func TestFileSection(t *testing.T) {
+	f := morebytes.NewFile([]byte("Hello, world!"))
+	s := f.Section(7, 5)
+
+	b, err := io.ReadAll(s)
+	if string(b) != "world" || err != nil {
+		t.Fatalf(`ReadAll(s) = %q, %v; want "world", <nil>`, b, err)
+	}
+
+	s.Seek(0, io.SeekStart)
+	n, err := s.WriteString("gophers")
+	if n != 5 || err != morebytes.ErrFileSizeLimit {
+		t.Fatalf(`s.WriteString("gophers") = %v, %v; want 5, ErrFileSizeLimit`, n, err)
+	}
+	if want := "Hello, gophe!"; f.String() != want {
+		t.Fatalf("f contents = %q; want %q", f.String(), want)
+	}
+
+	if s := f.Section(10, 100); s.String() != "he!" {
+		t.Fatalf(`Section(10, 100) = %q; want "he!"`, s.String())
+	}
+	if s := f.Section(100, 1); s.String() != "" {
+		t.Fatalf(`Section(100, 1) = %q; want ""`, s.String())
+	}
+
+	for _, args := range [][2]int64{{-1, 1}, {0, -1}} {
+		func() {
+			defer func() {
+				r := recover()
+				if msg, _ := r.(string); !strings.HasPrefix(msg, "morebytes: Section: ") {
+					t.Errorf("Section(%d, %d) panicked with %v; want a morebytes: Section panic", args[0], args[1], r)
+				}
+			}()
+			f.Section(args[0], args[1])
+		}()
+	}
+}
+
+func TestFileOffset(t *testing.T) {
+	f := morebytes.NewFile([]byte("Hello, world!"))
+	f.Next(5)
+	if off := f.Offset(); off != 5 {
+		t.Fatalf("after Next(5): Offset() = %v; want 5", off)
+	}
+
+	if err := f.SetOffset(100); err != nil {
+		t.Fatalf("SetOffset(100) = %v", err)
+	}
+	if off, _ := f.Seek(0, io.SeekCurrent); off != 100 {
+		t.Fatalf("after SetOffset(100): Seek(0, io.SeekCurrent) = %v; want 100", off)
+	}
+
+	if err := f.SetOffset(-1); err == nil {
+		t.Fatalf("SetOffset(-1) unexpectedly succeeded")
+	}
+	if off := f.Offset(); off != 100 {
+		t.Fatalf("after SetOffset(-1): Offset() = %v; want 100", off)
+	}
+
+	if off := f.Tell(); off != 100 {
+		t.Fatalf("Tell() = %v; want 100", off)
+	}
+
+	f.Rewind()
+	if off := f.Offset(); off != 0 {
+		t.Fatalf("after Rewind(): Offset() = %v; want 0", off)
+	}
+}
+
+func TestFileCompact(t *testing.T) {
+	f := morebytes.NewFixedFile(make([]byte, 0, 8))
+	f.WriteString("abcdef")
+	f.Seek(4, io.SeekStart)
+	f.Compact()
+
+	if want := "ef"; f.String() != want {
+		t.Fatalf("after Compact: contents = %q; want %q", f.String(), want)
+	}
+	if off := f.Offset(); off != 0 {
+		t.Fatalf("after Compact: Offset() = %v; want 0", off)
+	}
+
+	f.Seek(0, io.SeekEnd)
+	if n, err := f.WriteString("ghijkl"); n != 6 || err != nil {
+		t.Fatalf(`after Compact: WriteString("ghijkl") = %v, %v; want 6, <nil>`, n, err)
+	}
+	if want := "efghijkl"; f.String() != want {
+		t.Fatalf("contents = %q; want %q", f.String(), want)
+	}
+
+	f.Seek(100, io.SeekStart)
+	f.Compact()
+	if s := f.Size(); s != 0 {
+		t.Fatalf("after Compact past end: Size() = %v; want 0", s)
+	}
+
+	g := morebytes.NewFile(make([]byte, 1<<16))
+	g.Seek(-10, io.SeekEnd)
+	g.Compact()
+	if s, c := g.Size(), g.Cap(); s != 10 || c >= 1<<16 {
+		t.Fatalf("after Compact: Size() = %v, Cap() = %v; want 10, less than %v", s, c, 1<<16)
+	}
+}
+
+// A trickleWriter accepts at most N bytes per call to Write.
+type trickleWriter struct {
+	strings.Builder
+	N int
+}
+
+func (w *trickleWriter) Write(p []byte) (int, error) {
+	if len(p) > w.N {
+		p = p[:w.N]
+	}
+	return w.Builder.Write(p)
+}
+
+func TestFileWriteToShortWrites(t *testing.T) {
+	f := morebytes.NewFile([]byte("Hello, world!"))
+	w := &trickleWriter{N: 3}
+	n, err := f.WriteTo(w)
+	if n != 13 || err != nil {
+		t.Fatalf("WriteTo(_) = %v, %v; want 13, <nil>", n, err)
+	}
+	if want := "Hello, world!"; w.String() != want {
+		t.Fatalf("output = %q; want %q", w.String(), want)
+	}
+
+	f.Seek(0, io.SeekStart)
+	n, err = f.WriteTo(&trickleWriter{N: 0})
+	if n != 0 || err != io.ErrShortWrite {
+		t.Fatalf("WriteTo(<stuck writer>) = %v, %v; want 0, ErrShortWrite", n, err)
+	}
+}
+
+// A badCountWriter returns N from every call to Write.
+type badCountWriter struct{ N int }
+
+func (w badCountWriter) Write(p []byte) (int, error) { return w.N, nil }
+
+func TestFileWriteToInvalidCount(t *testing.T) {
+	for _, bad := range []int{-1, 100} {
+		f := morebytes.NewFile([]byte("Hello, world!"))
+		n, err := f.WriteTo(badCountWriter{N: bad})
+		if n != 0 || err == nil {
+			t.Errorf("WriteTo(<writer returning %d>) = %v, %v; want 0, non-nil", bad, n, err)
+		}
+		if off := f.Offset(); off != 0 {
+			t.Errorf("after WriteTo(<writer returning %d>): Offset() = %v; want 0", bad, off)
+		}
+	}
+}
+
+func TestFileIndex(t *testing.T) {
+	f := morebytes.NewFile([]byte("key=value; key2=value2"))
+	f.Next(4)
+
+	if i := f.IndexByte('='); i != 15 {
+		t.Errorf("IndexByte('=') = %v; want 15", i)
+	}
+	if i := f.IndexByte('!'); i != -1 {
+		t.Errorf("IndexByte('!') = %v; want -1", i)
+	}
+	if i := f.Index([]byte("value")); i != 4 {
+		t.Errorf(`Index("value") = %v; want 4`, i)
+	}
+	if i := f.Index([]byte("key")); i != 11 {
+		t.Errorf(`Index("key") = %v; want 11`, i)
+	}
+	if off := f.Offset(); off != 4 {
+		t.Errorf("Offset() = %v; want 4", off)
+	}
+}
+
+func TestFixedFileWriteAtPartial(t *testing.T) {
+	f := morebytes.NewFixedFile(make([]byte, 0, 10))
+
+	n, err := f.WriteAt([]byte("Hello, world!"), 3)
+	if n != 7 || err != morebytes.ErrFileSizeLimit {
+		t.Fatalf(`WriteAt("Hello, world!", 3) = %v, %v; want 7, ErrFileSizeLimit`, n, err)
+	}
+	if want := "\x00\x00\x00Hello, "; f.String() != want {
+		t.Fatalf("contents = %q; want %q", f.String(), want)
+	}
+
+	n, err = f.WriteAt([]byte("!"), 10)
+	if n != 0 || err != morebytes.ErrFileSizeLimit {
+		t.Fatalf(`WriteAt("!", 10) = %v, %v; want 0, ErrFileSizeLimit`, n, err)
+	}
+}
+
+func TestFileFill(t *testing.T) {
+	f := morebytes.NewFixedFile(make([]byte, 0, 8))
+	f.WriteString("id")
+
+	n, err := f.Fill(' ', 4)
+	if n != 4 || err != nil {
+		t.Fatalf("Fill(' ', 4) = %v, %v; want 4, <nil>", n, err)
+	}
+	n, err = f.Fill('.', 4)
+	if n != 2 || err != morebytes.ErrFileSizeLimit {
+		t.Fatalf("Fill('.', 4) = %v, %v; want 2, ErrFileSizeLimit", n, err)
+	}
+	if want := "id    .."; f.String() != want {
+		t.Fatalf("contents = %q; want %q", f.String(), want)
+	}
+}
+
+func TestFileReadLine(t *testing.T) {
+	f := morebytes.NewFile([]byte("one\ntwo\r\n\nthree"))
+	for _, want := range []struct {
+		line string
+		err  error
+	}{
+		{"one", nil},
+		{"two", nil},
+		{"", nil},
+		{"three", io.EOF},
+		{"", io.EOF},
+	} {
+		line, err := f.ReadLine()
+		if string(line) != want.line || err != want.err {
+			t.Fatalf("ReadLine() = %q, %v; want %q, %v", line, err, want.line, want.err)
+		}
+	}
+}
+
+func TestFileReadBytes(t *testing.T) {
+	f := morebytes.NewFile([]byte("a,b"))
+	line, err := f.ReadBytes(',')
+	if string(line) != "a," || err != nil {
+		t.Fatalf(`ReadBytes(',') = %q, %v; want "a,", <nil>`, line, err)
+	}
+	s, err := f.ReadString(',')
+	if s != "b" || err != io.EOF {
+		t.Fatalf(`ReadString(',') = %q, %v; want "b", EOF`, s, err)
+	}
+}
+
+func TestFileEqual(t *testing.T) {
+	var nilFile *morebytes.File
+	empty := new(morebytes.File)
+	hello := morebytes.NewFile([]byte("Hello"))
+	fixedHello := morebytes.NewFixedFile(append(make([]byte, 0, 10), "Hello"...))
+	fixedHello.Next(3)
+
+	for _, tc := range []struct {
+		desc string
+		f, g *morebytes.File
+		want bool
+	}{
+		{"nil, nil", nilFile, nilFile, true},
+		{"nil, empty", nilFile, empty, false},
+		{"empty, nil", empty, nilFile, false},
+		{"empty, empty", empty, new(morebytes.File), true},
+		{"empty, hello", empty, hello, false},
+		{"hello, fixedHello", hello, fixedHello, true},
+	} {
+		if got := tc.f.Equal(tc.g); got != tc.want {
+			t.Errorf("%s: Equal = %v; want %v", tc.desc, got, tc.want)
+		}
+	}
+}
+
+func TestFileReadAtSparse(t *testing.T) {
+	f := morebytes.NewFile([]byte("Hello"))
+	b := make([]byte, 8)
+
+	n, err := f.ReadAtSparse(b, 2, 10)
+	if n != 8 || err != nil || string(b[:n]) != "llo\x00\x00\x00\x00\x00" {
+		t.Fatalf("ReadAtSparse(_, 2, 10) = %v, %v, data %q", n, err, b[:n])
+	}
+
+	n, err = f.ReadAtSparse(b, 7, 10)
+	if n != 3 || err != io.EOF || string(b[:n]) != "\x00\x00\x00" {
+		t.Fatalf("ReadAtSparse(_, 7, 10) = %v, %v, data %q", n, err, b[:n])
+	}
+
+	n, err = f.ReadAtSparse(b, 10, 10)
+	if n != 0 || err != io.EOF {
+		t.Fatalf("ReadAtSparse(_, 10, 10) = %v, %v; want 0, EOF", n, err)
+	}
+
+	n, err = f.ReadAtSparse(b, 1, 0)
+	if n != 4 || err != io.EOF || string(b[:n]) != "ello" {
+		t.Fatalf("ReadAtSparse(_, 1, 0) = %v, %v, data %q", n, err, b[:n])
+	}
+}
+
+func TestFileWriteToN(t *testing.T) {
+	f := morebytes.NewFile([]byte("Hello, world!"))
+	w := new(strings.Builder)
+
+	n, err := f.WriteToN(w, 5)
+	if n != 5 || err != nil || w.String() != "Hello" {
+		t.Fatalf(`WriteToN(_, 5) = %v, %v, output %q; want 5, <nil>, "Hello"`, n, err, w.String())
+	}
+
+	w.Reset()
+	n, err = f.WriteToN(w, 10)
+	if n != 8 || err != io.EOF || w.String() != ", world!" {
+		t.Fatalf(`WriteToN(_, 10) = %v, %v, output %q; want 8, EOF, ", world!"`, n, err, w.String())
+	}
+	if r := f.Remaining(); r != 0 {
+		t.Fatalf("Remaining() = %v; want 0", r)
+	}
+}
+
+func TestFileFormat(t *testing.T) {
+	f := morebytes.NewFile([]byte("Hello, wörld!"))
+	f.Next(7)
+
+	for _, tc := range []struct {
+		format string
+		want   string
+	}{
+		{"%s", "Hello, wörld!"},
+		{"%v", "Hello, wörld!"},
+		{"%.5s", "Hello"},
+		{"%.9q", `"Hello, wö"`},
+		{"%8.3s", "     Hel"},
+		{"%-8.3s|", "Hel     |"},
+		{"%.3x", "48656c"},
+		{"%#v", `morebytes.File(size=14, offset=7, "Hello, wörld!")`},
+	} {
+		if got := fmt.Sprintf(tc.format, f); got != tc.want {
+			t.Errorf("Sprintf(%q, f) = %q; want %q", tc.format, got, tc.want)
+		}
+	}
+
+	var nilFile *morebytes.File
+	if got, want := fmt.Sprintf("%s %#v", nilFile, nilFile), "<nil> (*morebytes.File)(nil)"; got != want {
+		t.Errorf("Sprintf(_, nil) = %q; want %q", got, want)
+	}
+
+	big := morebytes.NewFile([]byte(strings.Repeat("x", 1<<20)))
+	want := fmt.Sprintf("morebytes.File(size=%d, offset=0, %q...)", 1<<20, strings.Repeat("x", 64))
+	if got := big.GoString(); got != want {
+		t.Errorf("GoString() = %q; want %q", got, want)
+	}
+}
+
+func TestFileAppendFrom(t *testing.T) {
+	f := morebytes.NewFile([]byte("Hello"))
+	g := morebytes.NewFile([]byte("XX, world!"))
+	g.Next(2)
+
+	n, err := f.AppendFrom(g)
+	if n != 8 || err != nil {
+		t.Fatalf("AppendFrom = %v, %v; want 8, <nil>", n, err)
+	}
+	if want := "Hello, world!"; f.String() != want {
+		t.Fatalf("after AppendFrom: contents = %q; want %q", f.String(), want)
+	}
+	if off := f.Offset(); off != 0 {
+		t.Errorf("after AppendFrom: f.Offset() = %v; want 0", off)
+	}
+	if r := g.Remaining(); r != 0 {
+		t.Errorf("after AppendFrom: g.Remaining() = %v; want 0", r)
+	}
+
+	ff := morebytes.NewFixedFile(make([]byte, 0, 8))
+	ff.WriteString("abc")
+	g.Seek(0, io.SeekStart)
+	n, err = ff.AppendFrom(g)
+	if n != 5 || err != morebytes.ErrFileSizeLimit || ff.String() != "abcXX, w" {
+		t.Fatalf("fixed: AppendFrom = %v, %v, contents %q; want 5, ErrFileSizeLimit, %q", n, err, ff.String(), "abcXX, w")
+	}
+	if off := g.Offset(); off != 5 {
+		t.Errorf("fixed: after AppendFrom: g.Offset() = %v; want 5", off)
+	}
+
+	self := morebytes.NewFile([]byte("ab"))
+	if n, err := self.AppendFrom(self); n != 2 || err != nil || self.String() != "abab" {
+		t.Fatalf("self: AppendFrom = %v, %v, contents %q; want 2, <nil>, %q", n, err, self.String(), "abab")
+	}
+}
+
+func TestFileTruncateAndShrink(t *testing.T) {
+	f := morebytes.NewFile(make([]byte, 1<<20))
+	copy(f.Bytes(), "Hello, world!")
+
+	if err := f.TruncateAndShrink(5); err != nil {
+		t.Fatal(err)
+	}
+	if f.String() != "Hello" || f.Cap() != 5 {
+		t.Errorf("after TruncateAndShrink(5): contents %q, Cap() = %v; want %q, 5", f.String(), f.Cap(), "Hello")
+	}
+
+	// A modest reduction in size does not reallocate.
+	g := morebytes.NewFile(make([]byte, 1<<20))
+	if err := g.TruncateAndShrink(1 << 19); err != nil {
+		t.Fatal(err)
+	}
+	if g.Cap() != 1<<20 {
+		t.Errorf("after TruncateAndShrink(1<<19): Cap() = %v; want %v", g.Cap(), 1<<20)
+	}
+
+	// A fixed File never reallocates.
+	ff := morebytes.NewFixedFile(make([]byte, 1<<20))
+	if err := ff.TruncateAndShrink(5); err != nil {
+		t.Fatal(err)
+	}
+	if ff.Size() != 5 || ff.Cap() != 1<<20 {
+		t.Errorf("fixed: after TruncateAndShrink(5): Size() = %v, Cap() = %v; want 5, %v", ff.Size(), ff.Cap(), 1<<20)
+	}
+	if err := ff.TruncateAndShrink(1<<20 + 1); err != morebytes.ErrFileSizeLimit {
+		t.Errorf("fixed: TruncateAndShrink(1<<20 + 1) = %v; want ErrFileSizeLimit", err)
+	}
+}
+
+func TestFileSetSizeLimit(t *testing.T) {
+	f := new(morebytes.File)
+	f.SetSizeLimit(8)
+	if limit := f.SizeLimit(); limit != 8 {
+		t.Fatalf("SizeLimit() = %v; want 8", limit)
+	}
+
+	n, err := f.WriteString("Hello, world!")
+	if n != 8 || err != morebytes.ErrFileSizeLimit || f.String() != "Hello, w" {
+		t.Fatalf("WriteString = %v, %v, contents %q; want 8, ErrFileSizeLimit, %q", n, err, f.String(), "Hello, w")
+	}
+
+	g := new(morebytes.File)
+	g.Reserve(64)
+	g.SetSizeLimit(10)
+	m, err := g.ReadFrom(strings.NewReader("Hello, world!"))
+	if m != 10 || err != morebytes.ErrFileSizeLimit || g.String() != "Hello, wor" {
+		t.Fatalf("ReadFrom = %v, %v, contents %q; want 10, ErrFileSizeLimit, %q", m, err, g.String(), "Hello, wor")
+	}
+	if err := g.Truncate(11); err != morebytes.ErrFileSizeLimit {
+		t.Fatalf("Truncate(11) = %v; want ErrFileSizeLimit", err)
+	}
+
+	// Removing the limit allows the File to grow again.
+	g.SetSizeLimit(-1)
+	if _, err := g.WriteString("ld!"); err != nil || g.String() != "Hello, world!" {
+		t.Fatalf("after SetSizeLimit(-1): WriteString = %v, contents %q", err, g.String())
+	}
+
+	// For a fixed File, the limit is clamped to the capacity.
+	ff := morebytes.NewFixedFile(make([]byte, 0, 4))
+	ff.SetSizeLimit(100)
+	if limit := ff.SizeLimit(); limit != 4 {
+		t.Fatalf("fixed: SizeLimit() = %v; want 4", limit)
+	}
+	ff.SetSizeLimit(2)
+	if limit := ff.SizeLimit(); limit != 2 {
+		t.Fatalf("fixed: SizeLimit() = %v; want 2", limit)
+	}
+}
+
+func TestFileAvailable(t *testing.T) {
+	ff := morebytes.NewFixedFile(make([]byte, 0, 16))
+	ff.WriteString("Hello")
+	if n := ff.Available(); n != 11 {
+		t.Fatalf("Available() = %v; want 11", n)
+	}
+
+	b := ff.AvailableBuffer()
+	if len(b) != 0 || cap(b) != 11 {
+		t.Fatalf("AvailableBuffer() has len %v, cap %v; want 0, 11", len(b), cap(b))
+	}
+	b = strconv.AppendInt(b, 12345, 10)
+	if _, err := ff.Write(b); err != nil {
+		t.Fatal(err)
+	}
+	if want := "Hello12345"; ff.String() != want {
+		t.Fatalf("after Write(AvailableBuffer()...): contents = %q; want %q", ff.String(), want)
+	}
+
+	ff.Seek(100, io.SeekStart)
+	if n := ff.Available(); n != 0 {
+		t.Errorf("after Seek(100): Available() = %v; want 0", n)
+	}
+	if b := ff.AvailableBuffer(); cap(b) != 0 {
+		t.Errorf("after Seek(100): AvailableBuffer() has cap %v; want 0", cap(b))
+	}
+
+	f := new(morebytes.File)
+	if n := f.Available(); n < 1<<31-1 {
+		t.Errorf("zero File: Available() = %v; want effectively unlimited", n)
+	}
+}
+
+func TestFileReadAtBounds(t *testing.T) {
+	b := make([]byte, 16)
+	copy(b, "Hello, world!")
+	f := morebytes.NewFile(b[:5])
+
+	for _, off := range []int64{
+		5,                 // exactly at the end of the data
+		6,                 // within the capacity of the backing slice
+		int64(cap(b)),     // at the end of the backing slice
+		int64(cap(b)) + 1, // beyond the end of the backing slice
+		1<<63 - 1,         // the maximum possible offset
+	} {
+		buf := make([]byte, 4)
+		n, err := f.ReadAt(buf, off)
+		if n != 0 || err != io.EOF {
+			t.Errorf("ReadAt(_, %v) = %v, %v; want 0, EOF", off, n, err)
+		}
+
+		n, err = f.ReadAt(nil, off)
+		if n != 0 || err != io.EOF {
+			t.Errorf("ReadAt(nil, %v) = %v, %v; want 0, EOF", off, n, err)
+		}
+	}
+
+	buf := make([]byte, 4)
+	if n, err := f.ReadAt(buf, 4); n != 1 || err != io.EOF || buf[0] != 'o' {
+		t.Errorf("ReadAt(_, 4) = %v, %v, data %q; want 1, EOF, %q", n, err, buf[:n], "o")
+	}
+	if n, err := f.ReadAt(buf, -1); n != 0 || err == nil || err == io.EOF {
+		t.Errorf("ReadAt(_, -1) = %v, %v; want 0, non-EOF error", n, err)
+	}
+}
+
+func TestFileReadOnly(t *testing.T) {
+	f := morebytes.NewFile([]byte("Hello, world!"))
+	f.Next(7)
+	ro := f.ReadOnly()
+
+	if off := ro.Offset(); off != 0 {
+		t.Errorf("ReadOnly().Offset() = %v; want 0", off)
+	}
+	if b, err := ro.Peek(5); err != nil || string(b) != "Hello" {
+		t.Errorf("Peek(5) = %q, %v; want %q, <nil>", b, err, "Hello")
+	}
+	ro.Seek(7, io.SeekStart)
+	if b, err := io.ReadAll(ro); err != nil || string(b) != "world!" {
+		t.Errorf("ReadAll = %q, %v; want %q, <nil>", b, err, "world!")
+	}
+
+	for _, tc := range []struct {
+		name string
+		fn   func() error
+	}{
+		{"Write", func() error { _, err := ro.Write([]byte("x")); return err }},
+		{"WriteAt", func() error { _, err := ro.WriteAt([]byte("x"), 0); return err }},
+		{"WriteByte", func() error { return ro.WriteByte('x') }},
+		{"WriteRune", func() error { _, err := ro.WriteRune('x'); return err }},
+		{"WriteString", func() error { _, err := ro.WriteString("x"); return err }},
+		{"Truncate", func() error { return ro.Truncate(0) }},
+		{"Insert", func() error { return ro.Insert(0, []byte("x")) }},
+		{"Delete", func() error { return ro.Delete(0, 1) }},
+		{"Fill", func() error { _, err := ro.Fill('x', 1); return err }},
+		{"ReadFrom", func() error { _, err := ro.ReadFrom(strings.NewReader("x")); return err }},
+		{"AppendFrom", func() error { _, err := ro.AppendFrom(morebytes.NewFile([]byte("x"))); return err }},
+		{"UnmarshalBinary", func() error { return ro.UnmarshalBinary([]byte("x")) }},
+	} {
+		ro.Seek(0, io.SeekStart)
+		if err := tc.fn(); err != morebytes.ErrReadOnly {
+			t.Errorf("%s: %v; want ErrReadOnly", tc.name, err)
+		}
+	}
+	if n := ro.Available(); n != 0 {
+		t.Errorf("Available() = %v; want 0", n)
+	}
+	if b := ro.AvailableBuffer(); b != nil {
+		t.Errorf("AvailableBuffer() = %q; want nil", b)
+	}
+	if want := "Hello, world!"; f.String() != want || ro.String() != want {
+		t.Errorf("after failed writes: contents %q, %q; want %q", f.String(), ro.String(), want)
+	}
+
+	// Sections of a read-only File are also read-only.
+	if _, err := ro.Section(0, 5).Write([]byte("x")); err != morebytes.ErrReadOnly {
+		t.Errorf("Section(0, 5).Write: %v; want ErrReadOnly", err)
+	}
+
+	// Compact does not move the underlying data.
+	ro.Seek(7, io.SeekStart)
+	ro.Compact()
+	if ro.String() != "world!" || f.String() != "Hello, world!" {
+		t.Errorf("after Compact: contents %q, original %q; want %q, %q", ro.String(), f.String(), "world!", "Hello, world!")
+	}
+}
+
+func TestFileHashTo(t *testing.T) {
+	f := morebytes.NewFile([]byte("Hello, world!"))
+	f.Next(7)
+
+	want := sha256.Sum256([]byte("Hello, world!"))
+	if got := f.HashTo(sha256.New()); !bytes.Equal(got, want[:]) {
+		t.Errorf("HashTo(sha256.New()) = %x; want %x", got, want)
+	}
+	if off := f.Offset(); off != 7 {
+		t.Errorf("after HashTo: Offset() = %v; want 7", off)
+	}
+}
+
+func TestFileFork(t *testing.T) {
+	base := morebytes.NewFile([]byte("Hello, world!"))
+	base.Next(7)
+
+	fork := base.Fork()
+	if off := fork.Offset(); off != 7 {
+		t.Errorf("Fork().Offset() = %v; want 7", off)
+	}
+	if b, err := fork.Peek(5); err != nil || string(b) != "world" {
+		t.Errorf("Peek(5) = %q, %v; want %q, <nil>", b, err, "world")
+	}
+
+	if _, err := fork.WriteString("gophers"); err != nil {
+		t.Fatal(err)
+	}
+	if want := "Hello, gophers"; fork.String() != want {
+		t.Errorf("fork contents = %q; want %q", fork.String(), want)
+	}
+	if want := "Hello, world!"; base.String() != want {
+		t.Errorf("base contents = %q; want %q", base.String(), want)
+	}
+
+	for _, tc := range []struct {
+		name string
+		fn   func(*morebytes.File)
+	}{
+		{"WriteAt", func(f *morebytes.File) { f.WriteAt([]byte("J"), 0) }},
+		{"WriteByte", func(f *morebytes.File) { f.Seek(0, io.SeekStart); f.WriteByte('J') }},
+		{"Truncate", func(f *morebytes.File) { f.Truncate(1); f.Truncate(13) }},
+		{"Delete", func(f *morebytes.File) { f.Delete(0, 1) }},
+		{"Insert", func(f *morebytes.File) { f.Insert(0, []byte("J")) }},
+		{"Compact", func(f *morebytes.File) { f.Compact() }},
+		{"ReadFrom", func(f *morebytes.File) { f.Seek(0, io.SeekStart); f.ReadFrom(strings.NewReader("J")) }},
+		{"UnmarshalBinary", func(f *morebytes.File) { f.UnmarshalBinary([]byte("J")) }},
+		{"Section.Write", func(f *morebytes.File) { f.Section(0, 1).Write([]byte("J")) }},
+	} {
+		for _, base := range []*morebytes.File{
+			morebytes.NewFile([]byte("Hello, world!")),
+			morebytes.NewFixedFile([]byte("Hello, world!")),
+		} {
+			tc.fn(base.Fork())
+			if want := "Hello, world!"; base.String() != want {
+				t.Errorf("%s: base contents = %q; want %q", tc.name, base.String(), want)
+			}
+		}
+	}
+
+	// A fork of a fixed File retains its size limit.
+	ff := morebytes.NewFixedFile(make([]byte, 5, 8))
+	fork = ff.Fork()
+	fork.Write([]byte("x"))
+	if limit := fork.SizeLimit(); limit != 8 {
+		t.Errorf("fork of fixed File: SizeLimit() = %v; want 8", limit)
+	}
+}
+
+func TestFileWriteAtReport(t *testing.T) {
+	f := morebytes.NewFile([]byte("Hello, world!"))
+
+	n, grew, err := f.WriteAtReport([]byte("J"), 0)
+	if n != 1 || grew != 0 || err != nil {
+		t.Errorf("WriteAtReport(\"J\", 0) = %v, %v, %v; want 1, 0, <nil>", n, grew, err)
+	}
+
+	n, grew, err = f.WriteAtReport([]byte("!!!"), 12)
+	if n != 3 || grew != 2 || err != nil {
+		t.Errorf("WriteAtReport(\"!!!\", 12) = %v, %v, %v; want 3, 2, <nil>", n, grew, err)
+	}
+	if want := "Jello, world!!!"; f.String() != want {
+		t.Errorf("contents = %q; want %q", f.String(), want)
+	}
+
+	ff := morebytes.NewFixedFile(make([]byte, 2, 4))
+	n, grew, err = ff.WriteAtReport([]byte("abcd"), 1)
+	if n != 3 || grew != 2 || err != morebytes.ErrFileSizeLimit {
+		t.Errorf("fixed: WriteAtReport(\"abcd\", 1) = %v, %v, %v; want 3, 2, ErrFileSizeLimit", n, grew, err)
+	}
+}
+
+func TestFileWriteAtReportConcurrent(t *testing.T) {
+	f := new(morebytes.File)
+
+	const (
+		writers = 8
+		chunk   = 16
+	)
+	var (
+		wg    sync.WaitGroup
+		total int64
+		mu    sync.Mutex
+	)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, grew, err := f.WriteAtReport(make([]byte, chunk), int64(i*chunk))
+			if err != nil {
+				t.Error(err)
+			}
+			mu.Lock()
+			total += grew
+			mu.Unlock()
+		}(i)
+	}
+	wg.Wait()
+
+	if total != f.Size() {
+		t.Errorf("total growth reported = %v; want %v", total, f.Size())
+	}
+}
+
+func TestFileBytesAppend(t *testing.T) {
+	f := morebytes.NewFile(make([]byte, 0, 64))
+	io.WriteString(f, "Hello, world!")
+	f.Truncate(5)
+
+	b := f.Bytes()
+	if len(b) != 5 || cap(b) != 5 {
+		t.Fatalf("Bytes() has len %v, cap %v; want 5, 5", len(b), cap(b))
+	}
+	_ = append(b, " there"...)
+
+	// Growing the File must not expose the bytes appended to the Bytes slice.
+	f.Seek(0, io.SeekEnd)
+	f.Write([]byte{'!'})
+	if want := "Hello!"; f.String() != want {
+		t.Errorf("after append to Bytes() and Write: contents %q; want %q", f.String(), want)
+	}
+}
+
+func TestFileBytesCopy(t *testing.T) {
+	f := morebytes.NewFile([]byte("Hello, world!"))
+
+	b := f.BytesCopy()
+	if string(b) != "Hello, world!" {
+		t.Fatalf("BytesCopy() = %q; want %q", b, "Hello, world!")
+	}
+
+	b[0] = 'J'
+	if f.String() != "Hello, world!" {
+		t.Errorf("after modifying BytesCopy() result: contents %q; want unchanged", f.String())
+	}
+	f.WriteAt([]byte("Y"), 0)
+	if string(b) != "Jello, world!" {
+		t.Errorf("after WriteAt: BytesCopy() result %q; want unchanged", b)
+	}
+}
+
+func TestFileConcurrentReadAtWriteAt(t *testing.T) {
+	const prefix = "Hello, world!"
+	f := morebytes.NewFile(make([]byte, 0, len(prefix)))
+	io.WriteString(f, prefix)
+
+	const (
+		writers = 4
+		readers = 4
+		writes  = 100
+		chunk   = 64
+	)
+
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			b := bytes.Repeat([]byte{byte('a' + i)}, chunk)
+			for j := 0; j < writes; j++ {
+				// Each write lands in a region disjoint from the prefix and from
+				// every other write, and most of them grow (and reallocate) the
+				// File's backing slice.
+				off := int64(len(prefix) + (j*writers+i)*chunk)
+				if _, err := f.WriteAt(b, off); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}(i)
+	}
+	for i := 0; i < readers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b := make([]byte, len(prefix))
+			for j := 0; j < writes; j++ {
+				n, err := f.ReadAt(b, 0)
+				if n != len(prefix) || err != nil || string(b) != prefix {
+					t.Errorf("ReadAt(_, 0) = %v, %v (%q); want %v, <nil> (%q)", n, err, b[:n], len(prefix), prefix)
+					return
+				}
+				if _, err := f.ReadAtSparse(b, 0, 0); err != nil || string(b) != prefix {
+					t.Errorf("ReadAtSparse(_, 0, 0) = _, %v (%q); want <nil> (%q)", err, b, prefix)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if want := int64(len(prefix) + writers*writes*chunk); f.Size() != want {
+		t.Errorf("Size() = %v; want %v", f.Size(), want)
+	}
+}
+
+func TestFileCommit(t *testing.T) {
+	ff := morebytes.NewFixedFile(make([]byte, 0, 16))
+	ff.WriteString("Hello, world!")
+	ff.Seek(7, io.SeekStart)
+
+	// Overwrite a prefix of the existing data.
+	b := append(ff.AvailableBuffer(), "W"...)
+	ff.Commit(len(b))
+	if want := "Hello, World!"; ff.String() != want || ff.Offset() != 8 {
+		t.Fatalf("after Commit(1): contents %q, offset %v; want %q, 8", ff.String(), ff.Offset(), want)
+	}
+
+	// Extend the data past its current size.
+	ff.Seek(0, io.SeekEnd)
+	b = strconv.AppendInt(ff.AvailableBuffer(), 123, 10)
+	ff.Commit(len(b))
+	if want := "Hello, World!123"; ff.String() != want || ff.Offset() != 16 {
+		t.Fatalf("after Commit(3): contents %q, offset %v; want %q, 16", ff.String(), ff.Offset(), want)
+	}
+
+	// The buffer is now full, so committing any further bytes is invalid.
+	ff.Commit(0)
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("Commit(1) beyond capacity did not panic")
+			}
+		}()
+		ff.Commit(1)
+	}()
+}
+
+func TestFileFull(t *testing.T) {
+	ff := morebytes.NewFixedFile(make([]byte, 0, 5))
+	if ff.Full() {
+		t.Errorf("empty fixed File: Full() = true; want false")
+	}
+
+	io.Copy(ff, strings.NewReader("Hi"))
+	if ff.Full() {
+		t.Errorf("after copying a short stream: Full() = true; want false")
+	}
+
+	if _, err := io.Copy(ff, strings.NewReader(", world!")); err != morebytes.ErrFileSizeLimit {
+		t.Fatalf("io.Copy of a long stream: err = %v; want ErrFileSizeLimit", err)
+	}
+	if !ff.Full() {
+		t.Errorf("after copying a long stream: Full() = false; want true")
+	}
+
+	// Reading all of the data does not change whether the File is full.
+	io.Copy(io.Discard, ff)
+	if !ff.Full() {
+		t.Errorf("after reading all data: Full() = false; want true")
+	}
+
+	f := morebytes.NewFile([]byte("Hello"))
+	if f.Full() {
+		t.Errorf("non-fixed File: Full() = true; want false")
+	}
+	f.SetSizeLimit(5)
+	if !f.Full() {
+		t.Errorf("after SetSizeLimit(5): Full() = false; want true")
+	}
+}
+
+func TestFileStrictUnread(t *testing.T) {
+	f := morebytes.NewFile([]byte("héllo"))
+
+	// By default, UnreadRune backs up over the preceding rune regardless of how
+	// the offset got there.
+	f.Seek(3, io.SeekStart)
+	if err := f.UnreadRune(); err != nil || f.Offset() != 1 {
+		t.Fatalf("lenient: UnreadRune() = %v, offset %v; want <nil>, 1", err, f.Offset())
+	}
+
+	f.SetStrictUnread(true)
+	if err := f.UnreadRune(); err == nil {
+		t.Errorf("strict: UnreadRune after Seek succeeded unexpectedly")
+	}
+	if err := f.UnreadByte(); err == nil {
+		t.Errorf("strict: UnreadByte after Seek succeeded unexpectedly")
+	}
+
+	if r, size, err := f.ReadRune(); r != 'é' || size != 2 || err != nil {
+		t.Fatalf("ReadRune() = %q, %v, %v; want 'é', 2, <nil>", r, size, err)
+	}
+	if err := f.UnreadRune(); err != nil || f.Offset() != 1 {
+		t.Fatalf("strict: UnreadRune after ReadRune = %v, offset %v; want <nil>, 1", err, f.Offset())
+	}
+	if err := f.UnreadRune(); err == nil {
+		t.Errorf("strict: second UnreadRune succeeded unexpectedly")
+	}
+
+	f.ReadRune()
+	if err := f.UnreadByte(); err != nil || f.Offset() != 2 {
+		t.Fatalf("strict: UnreadByte after ReadRune = %v, offset %v; want <nil>, 2", err, f.Offset())
+	}
+
+	f.Seek(3, io.SeekStart)
+	f.ReadByte()
+	if err := f.UnreadRune(); err == nil {
+		t.Errorf("strict: UnreadRune after ReadByte succeeded unexpectedly")
+	}
+
+	f.ReadByte()
+	f.Read(make([]byte, 1))
+	if err := f.UnreadByte(); err == nil {
+		t.Errorf("strict: UnreadByte after Read succeeded unexpectedly")
+	}
+
+	// A ReadByte at EOF fails, so there is nothing to unread.
+	f.ReadByte()
+	if err := f.UnreadByte(); err == nil {
+		t.Errorf("strict: UnreadByte after ReadByte at EOF succeeded unexpectedly")
+	}
+
+	f.Seek(-1, io.SeekEnd)
+	f.ReadByte()
+	f.ReadByte()
+	if err := f.UnreadByte(); err == nil {
+		t.Errorf("strict: UnreadByte after failed ReadByte succeeded unexpectedly")
+	}
+
+	f.Rewind()
+	f.ReadByte()
+	if err := f.UnreadByte(); err != nil || f.Offset() != 0 {
+		t.Errorf("strict: UnreadByte after ReadByte = %v, offset %v; want <nil>, 0", err, f.Offset())
+	}
+}
+
+func TestFileReadFromAt(t *testing.T) {
+	f := morebytes.NewFile(nil)
+	io.WriteString(f, "Hello")
+
+	// Assemble the rest of the File from out-of-order chunks.
+	if n, err := f.ReadFromAt(strings.NewReader("world!"), 7); n != 6 || err != nil {
+		t.Fatalf(`ReadFromAt("world!", 7) = %v, %v; want 6, <nil>`, n, err)
+	}
+	if n, err := f.ReadFromAt(strings.NewReader(", "), 5); n != 2 || err != nil {
+		t.Fatalf(`ReadFromAt(", ", 5) = %v, %v; want 2, <nil>`, n, err)
+	}
+	if want := "Hello, world!"; f.String() != want || f.Offset() != 5 {
+		t.Errorf("contents %q, offset %v; want %q, 5", f.String(), f.Offset(), want)
+	}
+
+	errRead := errors.New("read error")
+	r := io.MultiReader(strings.NewReader("?"), iotest.ErrReader(errRead))
+	if n, err := f.ReadFromAt(r, 12); n != 1 || err != errRead {
+		t.Errorf("ReadFromAt(<error after 1 byte>, 12) = %v, %v; want 1, %v", n, err, errRead)
+	}
+
+	ff := morebytes.NewFixedFile(make([]byte, 0, 8))
+	if n, err := ff.ReadFromAt(strings.NewReader("Hello, world!"), 2); n != 6 || err != morebytes.ErrFileSizeLimit {
+		t.Errorf("fixed: ReadFromAt = %v, %v; want 6, ErrFileSizeLimit", n, err)
+	}
+	if want := "\x00\x00Hello,"; ff.String() != want {
+		t.Errorf("fixed: contents %q; want %q", ff.String(), want)
+	}
+}
+
+func TestFileReadFromAtConcurrent(t *testing.T) {
+	f := new(morebytes.File)
+
+	const (
+		chunks = 8
+		chunk  = 1000
+	)
+	var wg sync.WaitGroup
+	for i := 0; i < chunks; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			r := strings.NewReader(strings.Repeat(string(rune('a'+i)), chunk))
+			if _, err := f.ReadFromAt(r, int64(i*chunk)); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 0; i < chunks; i++ {
+		want := strings.Repeat(string(rune('a'+i)), chunk)
+		if got := string(f.Bytes()[i*chunk:][:chunk]); got != want {
+			t.Errorf("chunk %d = %q...; want %q...", i, got[:8], want[:8])
+		}
+	}
+}
+
+func TestFileSectionReader(t *testing.T) {
+	f := morebytes.NewFile([]byte("Hello, world!"))
+	f.Seek(3, io.SeekStart)
+
+	sr := f.SectionReader(7, 5)
+	if sr.Size() != 5 {
+		t.Errorf("Size() = %v; want 5", sr.Size())
+	}
+	b, err := io.ReadAll(sr)
+	if string(b) != "world" || err != nil {
+		t.Errorf("ReadAll = %q, %v; want %q, <nil>", b, err, "world")
+	}
+	if f.Offset() != 3 {
+		t.Errorf("after reading SectionReader: f.Offset() = %v; want 3", f.Offset())
+	}
+
+	// The reader observes writes made after it was created.
+	f.WriteAt([]byte("W"), 7)
+	sr.Seek(0, io.SeekStart)
+	if b, _ := io.ReadAll(sr); string(b) != "World" {
+		t.Errorf("after WriteAt: ReadAll = %q; want %q", b, "World")
+	}
+
+	// A section beyond the end of the File reads only the existing data.
+	if b, err := io.ReadAll(f.SectionReader(10, 100)); string(b) != "ld!" || err != nil {
+		t.Errorf("ReadAll(SectionReader(10, 100)) = %q, %v; want %q, <nil>", b, err, "ld!")
+	}
+}
+
+func TestFileFixed(t *testing.T) {
+	for _, tc := range []struct {
+		desc string
+		f    *morebytes.File
+		want bool
+	}{
+		{"zero File", new(morebytes.File), false},
+		{"NewFile", morebytes.NewFile(make([]byte, 0, 8)), false},
+		{"NewFixedFile", morebytes.NewFixedFile(make([]byte, 0, 8)), true},
+		{"NewRingFile", morebytes.NewRingFile(make([]byte, 0, 8)), true},
+		{"Section", morebytes.NewFile([]byte("Hello")).Section(1, 3), true},
+		{"Clone of NewFixedFile", morebytes.NewFixedFile(make([]byte, 0, 8)).Clone(), true},
+	} {
+		if got := tc.f.Fixed(); got != tc.want {
+			t.Errorf("%s: Fixed() = %v; want %v", tc.desc, got, tc.want)
+		}
+	}
+}
+
+func TestFileReader(t *testing.T) {
+	for _, f := range []*morebytes.File{
+		morebytes.NewFile(make([]byte, 0, 64)),
+		morebytes.NewFixedFile(make([]byte, 0, 64)),
+	} {
+		io.WriteString(f, "Hello, world!")
+
+		r := f.Reader()
+		if r.Size() != 13 {
+			t.Errorf("Reader().Size() = %v; want 13", r.Size())
+		}
+
+		// Modifications to f after the snapshot, whether overwriting or appending,
+		// are not visible through the Reader.
+		f.WriteAt([]byte("J"), 0)
+		io.WriteString(f, " Goodbye!")
+		f.Seek(7, io.SeekStart)
+		f.WriteByte('W')
+
+		b, err := io.ReadAll(r)
+		if string(b) != "Hello, world!" || err != nil {
+			t.Errorf("ReadAll(Reader()) = %q, %v; want %q, <nil>", b, err, "Hello, world!")
+		}
+		if want := "Jello, World! Goodbye!"; f.String() != want {
+			t.Errorf("f contents = %q; want %q", f.String(), want)
+		}
+		if f.Offset() != 8 {
+			t.Errorf("f.Offset() = %v; want 8", f.Offset())
+		}
+	}
+}
+
+func TestFileReaderView(t *testing.T) {
+	f := morebytes.NewFile(make([]byte, 0, 64))
+	io.WriteString(f, "Hello, world!")
+
+	for _, v := range []*morebytes.File{
+		f.ReadOnly(),
+		f.Section(0, 5),
+	} {
+		r := v.Reader()
+
+		// Writes through the parent File are not visible through the Reader.
+		f.WriteAt([]byte("J"), 0)
+
+		b, err := io.ReadAll(r)
+		if want := "Hello"; string(b[:5]) != want || err != nil {
+			t.Errorf("ReadAll(Reader()) = %q, %v; want prefix %q, <nil>", b, err, want)
+		}
+		f.WriteAt([]byte("H"), 0)
+	}
+
+	// A fixed File continues to write to its original slice after Reader.
+	buf := make([]byte, 0, 16)
+	fixed := morebytes.NewFixedFile(buf)
+	io.WriteString(fixed, "Hello")
+	r := fixed.Reader()
+	fixed.WriteAt([]byte("J"), 0)
+	if got := string(buf[:5]); got != "Jello" {
+		t.Errorf("backing slice of fixed File = %q; want %q", got, "Jello")
+	}
+	if b, _ := io.ReadAll(r); string(b) != "Hello" {
+		t.Errorf("ReadAll(Reader()) = %q; want %q", b, "Hello")
+	}
+}
+
+func TestFileReaderConcurrent(t *testing.T) {
+	f := morebytes.NewFile(make([]byte, 0, 1024))
+	io.WriteString(f, strings.Repeat("a", 512))
+
+	r := f.Reader()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		b, err := io.ReadAll(iotest.OneByteReader(r))
+		if err != nil || string(b) != strings.Repeat("a", 512) {
+			t.Errorf("ReadAll(Reader()) = %q, %v; want 512 bytes of 'a'", b, err)
+		}
+	}()
+
+	f.Rewind()
+	for i := 0; i < 1024; i++ {
+		f.WriteByte('b')
+	}
+	<-done
+}
+
+func TestFileWriteAtZeroFillsGap(t *testing.T) {
+	stale := func() *morebytes.File {
+		// A File whose backing slice has spare capacity filled with stale data.
+		f := morebytes.NewFile(make([]byte, 0, 256))
+		f.Fill('x', 256)
+		f.Truncate(0)
+		f.Rewind()
+		return f
+	}
+
+	for _, tc := range []struct {
+		desc  string
+		f     *morebytes.File
+		write func(*morebytes.File)
+	}{
+		{
+			desc:  "WriteAt on empty File",
+			f:     new(morebytes.File),
+			write: func(f *morebytes.File) { f.WriteAt([]byte("!"), 100) },
+		},
+		{
+			desc:  "WriteAt with stale capacity",
+			f:     stale(),
+			write: func(f *morebytes.File) { f.WriteAt([]byte("!"), 100) },
+		},
+		{
+			desc:  "Write after Seek with stale capacity",
+			f:     stale(),
+			write: func(f *morebytes.File) { f.Seek(100, io.SeekStart); f.Write([]byte("!")) },
+		},
+		{
+			desc:  "WriteByte after Seek with stale capacity",
+			f:     stale(),
+			write: func(f *morebytes.File) { f.Seek(100, io.SeekStart); f.WriteByte('!') },
+		},
+		{
+			desc:  "ReadFrom after Seek with stale capacity",
+			f:     stale(),
+			write: func(f *morebytes.File) { f.Seek(100, io.SeekStart); f.ReadFrom(strings.NewReader("!")) },
+		},
+		{
+			desc: "Commit after Seek with stale capacity",
+			f:    stale(),
+			write: func(f *morebytes.File) {
+				f.Seek(100, io.SeekStart)
+				b := append(f.AvailableBuffer(), '!')
+				f.Commit(len(b))
+			},
+		},
+	} {
+		tc.write(tc.f)
+
+		if size := tc.f.Size(); size != 101 {
+			t.Errorf("%s: Size() = %v; want 101", tc.desc, size)
+			continue
+		}
+		b := make([]byte, 100)
+		if _, err := tc.f.ReadAt(b, 0); err != nil {
+			t.Fatalf("%s: ReadAt(_, 0) = %v", tc.desc, err)
+		}
+		if !bytes.Equal(b, make([]byte, 100)) {
+			t.Errorf("%s: bytes [0, 100) = %q; want all zeroes", tc.desc, b)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreio
+
+import (
+	"io"
+)
+
+// A Flusher is a buffered destination whose pending data can be forced
+// through to the underlying sink.
+type Flusher interface {
+	// Flush writes any buffered data to the underlying sink.
+	Flush() error
+}
+
+// A WriteFlusher is a Writer that buffers its data and can be flushed.
+type WriteFlusher interface {
+	io.Writer
+	Flusher
+}
+
+// A BufferedWriter accumulates writes in a buffer and writes them to an
+// underlying Writer when the buffer is full or when Flush is called.
+//
+// It is similar to bufio.Writer, but additionally reports the number of bytes
+// pending in the buffer so that callers can make their own flush decisions.
+//
+// If a write to the underlying Writer fails, the BufferedWriter returns that
+// error from all subsequent calls to Write and Flush.
+type BufferedWriter struct {
+	w   io.Writer
+	buf []byte // pending data; cap(buf) is the buffer size
+	err error
+}
+
+var _ WriteFlusher = (*BufferedWriter)(nil)
+
+// NewBufferedWriter returns a BufferedWriter that writes to w using a buffer of
+// the given size.
+func NewBufferedWriter(w io.Writer, size int) *BufferedWriter {
+	if size <= 0 {
+		panic("NewBufferedWriter: size must be positive")
+	}
+	return &BufferedWriter{w: w, buf: make([]byte, 0, size)}
+}
+
+// Pending returns the number of bytes that have been written to b but not yet
+// flushed to the underlying Writer.
+func (b *BufferedWriter) Pending() int {
+	return len(b.buf)
+}
+
+// Available returns the number of bytes that can be written to b before its
+// buffer is full.
+func (b *BufferedWriter) Available() int {
+	return cap(b.buf) - len(b.buf)
+}
+
+// Size returns the size of b's buffer.
+func (b *BufferedWriter) Size() int {
+	return cap(b.buf)
+}
+
+// Flush writes any pending data to the underlying Writer.
+func (b *BufferedWriter) Flush() error {
+	if b.err != nil {
+		return b.err
+	}
+	if len(b.buf) == 0 {
+		return nil
+	}
+
+	n, err := b.w.Write(b.buf)
+	if err == nil && n < len(b.buf) {
+		err = io.ErrShortWrite
+	}
+	if err != nil {
+		// Retain the data that was not written, so that Pending continues to
+		// report it.
+		b.buf = b.buf[:copy(b.buf, b.buf[n:])]
+		b.err = err
+		return err
+	}
+	b.buf = b.buf[:0]
+	return nil
+}
+
+func (b *BufferedWriter) Write(p []byte) (n int, err error) {
+	for len(p) > b.Available() && b.err == nil {
+		var m int
+		if len(b.buf) == 0 {
+			// Nothing is pending, so write the large chunk directly to avoid a copy.
+			m, b.err = b.w.Write(p)
+			if b.err == nil && m < len(p) {
+				b.err = io.ErrShortWrite
+			}
+		} else {
+			m = copy(b.buf[len(b.buf):cap(b.buf)], p)
+			b.buf = b.buf[:len(b.buf)+m]
+			b.Flush()
+		}
+		n += m
+		p = p[m:]
+	}
+	if b.err != nil {
+		return n, b.err
+	}
+	b.buf = append(b.buf, p...)
+	return n + len(p), nil
+}
+
+func (b *BufferedWriter) WriteString(s string) (n int, err error) {
+	for len(s) > b.Available() && b.err == nil {
+		m := copy(b.buf[len(b.buf):cap(b.buf)], s)
+		b.buf = b.buf[:len(b.buf)+m]
+		n += m
+		s = s[m:]
+		b.Flush()
+	}
+	if b.err != nil {
+		return n, b.err
+	}
+	b.buf = append(b.buf, s...)
+	return n + len(s), nil
+}
+
+func (b *BufferedWriter) WriteByte(c byte) error {
+	if b.err != nil {
+		return b.err
+	}
+	if b.Available() <= 0 && b.Flush() != nil {
+		return b.err
+	}
+	b.buf = append(b.buf, c)
+	return nil
+}
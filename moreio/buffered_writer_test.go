@@ -0,0 +1,85 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreio_test
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bcmills/more/moreio"
+)
+
+func TestBufferedWriter(t *testing.T) {
+	rw := new(recordingWriter)
+	bw := moreio.NewBufferedWriter(rw, 4)
+
+	io.WriteString(bw, "He")
+	bw.WriteByte('l')
+	if n := bw.Pending(); n != 3 {
+		t.Fatalf("Pending() = %v; want 3", n)
+	}
+	if len(rw.writes) != 0 {
+		t.Fatalf("wrote %q before the buffer was full", rw.writes)
+	}
+
+	bw.Write([]byte("lo, "))
+	bw.WriteByte('w')
+	io.WriteString(bw, "orld!")
+	if n := bw.Pending(); n != 1 {
+		t.Errorf("Pending() = %v; want 1", n)
+	}
+	if err := bw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if n := bw.Pending(); n != 0 {
+		t.Errorf("after Flush: Pending() = %v; want 0", n)
+	}
+
+	want := []string{"Hell", "o, w", "orld", "!"}
+	if !reflect.DeepEqual(rw.writes, want) {
+		t.Errorf("writes = %q; want %q", rw.writes, want)
+	}
+}
+
+func TestBufferedWriterLargeWrite(t *testing.T) {
+	rw := new(recordingWriter)
+	bw := moreio.NewBufferedWriter(rw, 4)
+
+	// A large write with nothing pending bypasses the buffer.
+	if n, err := bw.Write([]byte("Hello, world!")); n != 13 || err != nil {
+		t.Fatalf("Write = %v, %v; want 13, <nil>", n, err)
+	}
+	bw.Flush()
+
+	want := []string{"Hello, world!"}
+	if !reflect.DeepEqual(rw.writes, want) {
+		t.Errorf("writes = %q; want %q", rw.writes, want)
+	}
+}
+
+func TestBufferedWriterError(t *testing.T) {
+	var sb strings.Builder
+	bw := moreio.NewBufferedWriter(moreio.LimitWriter(&sb, 6, errArbitrary), 4)
+
+	n, err := io.WriteString(bw, "Hello, world!")
+	if n != 8 || err != errArbitrary {
+		t.Errorf(`WriteString("Hello, world!") = %v, %v; want 8, errArbitrary`, n, err)
+	}
+	if got := sb.String(); got != "Hello," {
+		t.Errorf("wrote %q; want %q", got, "Hello,")
+	}
+	if n := bw.Pending(); n != 2 {
+		t.Errorf("Pending() = %v; want 2", n)
+	}
+
+	if err := bw.WriteByte('!'); err != errArbitrary {
+		t.Errorf("WriteByte after error = %v; want errArbitrary", err)
+	}
+	if err := bw.Flush(); err != errArbitrary {
+		t.Errorf("Flush after error = %v; want errArbitrary", err)
+	}
+}
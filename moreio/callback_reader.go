@@ -0,0 +1,31 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreio
+
+import (
+	"io"
+)
+
+// CallbackReader returns a Reader that reads from r and calls fn with the bytes
+// read by each call to Read, before Read returns them. It is like io.TeeReader,
+// but calls a function instead of writing to an io.Writer.
+//
+// fn must not modify the slice or retain it after it returns.
+func CallbackReader(r io.Reader, fn func([]byte)) io.Reader {
+	return &callbackReader{r: r, fn: fn}
+}
+
+type callbackReader struct {
+	r  io.Reader
+	fn func([]byte)
+}
+
+func (cr *callbackReader) Read(p []byte) (n int, err error) {
+	n, err = cr.r.Read(p)
+	if n > 0 {
+		cr.fn(p[:n])
+	}
+	return n, err
+}
@@ -0,0 +1,43 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreio_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/bcmills/more/moreio"
+)
+
+func TestCallbackReader(t *testing.T) {
+	var (
+		seen  strings.Builder
+		calls int
+	)
+	r := moreio.CallbackReader(strings.NewReader("Hello, moreio!"), func(b []byte) {
+		calls++
+		seen.Write(b)
+	})
+
+	buf := make([]byte, 4)
+	var out strings.Builder
+	for {
+		n, err := r.Read(buf)
+		out.Write(buf[:n])
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if out.String() != "Hello, moreio!" || seen.String() != out.String() {
+		t.Fatalf("read %q; callback saw %q", out.String(), seen.String())
+	}
+	if calls != 4 {
+		t.Fatalf("callback called %d times; want 4", calls)
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreio
+
+import (
+	"errors"
+	"io"
+)
+
+// ChunkWriter returns a WriteCloser that buffers the data written to it and
+// writes it to w only in multiples of chunk bytes, so that a sequence of small
+// writes becomes a sequence of larger, chunk-aligned writes to w.
+//
+// Write reports data as written as soon as it is accepted into the buffer.
+// The caller must call Close to write the final partial chunk (if any) to w.
+// Close does not close w.
+//
+// If a write to w fails, the ChunkWriter returns that error from all
+// subsequent calls to Write and Close.
+func ChunkWriter(w io.Writer, chunk int) io.WriteCloser {
+	if chunk <= 0 {
+		panic("ChunkWriter: chunk must be positive")
+	}
+	return &chunkWriter{w: w, chunk: chunk}
+}
+
+var errChunkWriterClosed = errors.New("moreio: write to closed ChunkWriter")
+
+type chunkWriter struct {
+	w     io.Writer
+	chunk int
+	buf   []byte
+	err   error
+}
+
+func (cw *chunkWriter) Write(p []byte) (n int, err error) {
+	if cw.err != nil {
+		return 0, cw.err
+	}
+
+	if len(cw.buf) > 0 {
+		// Complete the buffered chunk first.
+		m := cw.chunk - len(cw.buf)
+		if m > len(p) {
+			m = len(p)
+		}
+		cw.buf = append(cw.buf, p[:m]...)
+		n, p = m, p[m:]
+		if len(cw.buf) < cw.chunk {
+			return n, nil
+		}
+		if err := cw.flush(); err != nil {
+			return n, err
+		}
+	}
+
+	// Write any complete chunks directly from p, without copying them.
+	if full := len(p) / cw.chunk * cw.chunk; full > 0 {
+		m, err := cw.w.Write(p[:full])
+		n += m
+		if err == nil && m < full {
+			err = io.ErrShortWrite
+		}
+		if err != nil {
+			cw.err = err
+			return n, err
+		}
+		p = p[full:]
+	}
+
+	if cw.buf == nil {
+		cw.buf = make([]byte, 0, cw.chunk)
+	}
+	cw.buf = append(cw.buf, p...)
+	return n + len(p), nil
+}
+
+// flush writes the buffered data to cw.w.
+func (cw *chunkWriter) flush() error {
+	m, err := cw.w.Write(cw.buf)
+	if err == nil && m < len(cw.buf) {
+		err = io.ErrShortWrite
+	}
+	if err != nil {
+		cw.err = err
+		return err
+	}
+	cw.buf = cw.buf[:0]
+	return nil
+}
+
+func (cw *chunkWriter) Close() error {
+	if cw.err != nil {
+		return cw.err
+	}
+	if len(cw.buf) > 0 {
+		if err := cw.flush(); err != nil {
+			return err
+		}
+	}
+	cw.err = errChunkWriterClosed
+	return nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreio_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/bcmills/more/moreio"
+)
+
+// recordingWriter records the contents of each call to Write.
+type recordingWriter struct {
+	writes []string
+}
+
+func (rw *recordingWriter) Write(p []byte) (int, error) {
+	rw.writes = append(rw.writes, string(p))
+	return len(p), nil
+}
+
+func TestChunkWriter(t *testing.T) {
+	rw := new(recordingWriter)
+	w := moreio.ChunkWriter(rw, 4)
+
+	for _, s := range []string{"He", "l", "lo, ", "world!", "!"} {
+		if n, err := io.WriteString(w, s); n != len(s) || err != nil {
+			t.Fatalf("WriteString(%q) = %v, %v; want %v, <nil>", s, n, err, len(s))
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	want := []string{"Hell", "o, w", "orld", "!!"}
+	if len(rw.writes) != len(want) {
+		t.Fatalf("writes = %q; want %q", rw.writes, want)
+	}
+	for i := range want {
+		if rw.writes[i] != want[i] {
+			t.Fatalf("writes = %q; want %q", rw.writes, want)
+		}
+	}
+
+	if _, err := io.WriteString(w, "x"); err == nil {
+		t.Errorf("Write after Close succeeded unexpectedly")
+	}
+}
+
+func TestChunkWriterError(t *testing.T) {
+	w := moreio.ChunkWriter(moreio.FailAfter(4, errArbitrary), 4)
+
+	if n, err := io.WriteString(w, "Hello"); n != 5 || err != nil {
+		t.Fatalf(`WriteString("Hello") = %v, %v; want 5, <nil>`, n, err)
+	}
+	if n, err := io.WriteString(w, ", world!"); n != 3 || err != errArbitrary {
+		t.Fatalf(`WriteString(", world!") = %v, %v; want 3, errArbitrary`, n, err)
+	}
+	if err := w.Close(); err != errArbitrary {
+		t.Fatalf("Close() = %v; want errArbitrary", err)
+	}
+}
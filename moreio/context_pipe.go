@@ -0,0 +1,201 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreio
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// ContextPipe is like io.Pipe, but once ctx is done, calls to Read and Write on
+// either end of the pipe (including calls that are already blocked) fail with
+// ctx.Err(), so that a goroutine copying to or from the pipe is not blocked
+// forever if the goroutine on the other end has gone away.
+//
+// If an end of the pipe was closed (with Close or CloseWithError) before ctx
+// is done, calls on the other end continue to return the resulting error
+// (such as io.EOF from Read after the write end is closed).
+//
+// As with io.Pipe, reads and writes are matched one to one, with no internal
+// buffering, and it is safe to call Read and Write in parallel with each other
+// or with Close.
+func ContextPipe(ctx context.Context) (*PipeReader, *PipeWriter) {
+	p := &pipe{
+		ctx:  ctx,
+		wrCh: make(chan []byte),
+		rdCh: make(chan int),
+		done: make(chan struct{}),
+	}
+	return &PipeReader{p}, &PipeWriter{p}
+}
+
+// pipe is the shared state of a ContextPipe, patterned after the
+// implementation of io.Pipe.
+type pipe struct {
+	ctx  context.Context
+	wrMu sync.Mutex // serializes Write operations
+	wrCh chan []byte
+	rdCh chan int
+
+	once sync.Once // protects closing done
+	done chan struct{}
+
+	mu   sync.Mutex
+	rerr error // the error passed to PipeReader.CloseWithError, if any
+	werr error // the error passed to PipeWriter.CloseWithError, if any
+}
+
+func (p *pipe) read(b []byte) (n int, err error) {
+	select {
+	case <-p.done:
+		return 0, p.readCloseError()
+	default:
+	}
+	if err := p.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	select {
+	case bw := <-p.wrCh:
+		nr := copy(b, bw)
+		p.rdCh <- nr
+		return nr, nil
+	case <-p.done:
+		return 0, p.readCloseError()
+	case <-p.ctx.Done():
+		return 0, p.ctx.Err()
+	}
+}
+
+func (p *pipe) write(b []byte) (n int, err error) {
+	select {
+	case <-p.done:
+		return 0, p.writeCloseError()
+	default:
+	}
+	if err := p.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	p.wrMu.Lock()
+	defer p.wrMu.Unlock()
+
+	for once := true; once || len(b) > 0; once = false {
+		select {
+		case p.wrCh <- b:
+			nw := <-p.rdCh
+			b = b[nw:]
+			n += nw
+		case <-p.done:
+			return n, p.writeCloseError()
+		case <-p.ctx.Done():
+			return n, p.ctx.Err()
+		}
+	}
+	return n, nil
+}
+
+func (p *pipe) closeRead(err error) {
+	if err == nil {
+		err = io.ErrClosedPipe
+	}
+	p.mu.Lock()
+	if p.rerr == nil {
+		p.rerr = err
+	}
+	p.mu.Unlock()
+	p.once.Do(func() { close(p.done) })
+}
+
+func (p *pipe) closeWrite(err error) {
+	if err == nil {
+		err = io.EOF
+	}
+	p.mu.Lock()
+	if p.werr == nil {
+		p.werr = err
+	}
+	p.mu.Unlock()
+	p.once.Do(func() { close(p.done) })
+}
+
+// readCloseError returns the error for a Read after the pipe is closed.
+func (p *pipe) readCloseError() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.rerr == nil && p.werr != nil {
+		return p.werr
+	}
+	return io.ErrClosedPipe
+}
+
+// writeCloseError returns the error for a Write after the pipe is closed.
+func (p *pipe) writeCloseError() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.werr == nil && p.rerr != nil {
+		return p.rerr
+	}
+	return io.ErrClosedPipe
+}
+
+// A PipeReader is the read half of a pipe returned by ContextPipe.
+type PipeReader struct {
+	p *pipe
+}
+
+// Read implements the standard Read interface: it reads data from the pipe,
+// blocking until a writer arrives, the write end is closed, or the pipe's
+// context is done. If the write end is closed with an error, that error is
+// returned as err; otherwise err is io.EOF.
+func (r *PipeReader) Read(data []byte) (n int, err error) {
+	return r.p.read(data)
+}
+
+// Close closes the reader; subsequent writes to the write half of the pipe will
+// return the error io.ErrClosedPipe.
+func (r *PipeReader) Close() error {
+	return r.CloseWithError(nil)
+}
+
+// CloseWithError closes the reader; subsequent writes to the write half of the
+// pipe will return the error err, or io.ErrClosedPipe if err is nil.
+//
+// CloseWithError never overwrites the previous error if it exists and always
+// returns nil.
+func (r *PipeReader) CloseWithError(err error) error {
+	r.p.closeRead(err)
+	return nil
+}
+
+// A PipeWriter is the write half of a pipe returned by ContextPipe.
+type PipeWriter struct {
+	p *pipe
+}
+
+// Write implements the standard Write interface: it writes data to the pipe,
+// blocking until one or more readers have consumed all the data, the read end
+// is closed, or the pipe's context is done. If the read end is closed with an
+// error, that error is returned as err; otherwise err is io.ErrClosedPipe.
+func (w *PipeWriter) Write(data []byte) (n int, err error) {
+	return w.p.write(data)
+}
+
+// Close closes the writer; subsequent reads from the read half of the pipe will
+// return no bytes and io.EOF.
+func (w *PipeWriter) Close() error {
+	return w.CloseWithError(nil)
+}
+
+// CloseWithError closes the writer; subsequent reads from the read half of the
+// pipe will return no bytes and the error err, or io.EOF if err is nil.
+//
+// CloseWithError never overwrites the previous error if it exists and always
+// returns nil.
+func (w *PipeWriter) CloseWithError(err error) error {
+	w.p.closeWrite(err)
+	return nil
+}
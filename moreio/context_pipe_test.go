@@ -0,0 +1,80 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreio_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/bcmills/more/moreio"
+)
+
+func TestContextPipe(t *testing.T) {
+	r, w := moreio.ContextPipe(context.Background())
+
+	go func() {
+		io.WriteString(w, "Hello, world!")
+		w.Close()
+	}()
+	b, err := io.ReadAll(r)
+	if string(b) != "Hello, world!" || err != nil {
+		t.Errorf("ReadAll = %q, %v; want %q, <nil>", b, err, "Hello, world!")
+	}
+	r.Close()
+}
+
+func TestContextPipeCancelRead(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r, w := moreio.ContextPipe(ctx)
+	defer w.Close()
+
+	errc := make(chan error, 1)
+	go func() {
+		_, err := r.Read(make([]byte, 1))
+		errc <- err
+	}()
+
+	// The Read is blocked because nothing is writing to the pipe.
+	cancel()
+	if err := <-errc; err != context.Canceled {
+		t.Errorf("Read after cancel = _, %v; want %v", err, context.Canceled)
+	}
+	if _, err := io.WriteString(w, "Hello"); err != context.Canceled {
+		t.Errorf("Write after cancel = _, %v; want %v", err, context.Canceled)
+	}
+}
+
+func TestContextPipeCancelWrite(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r, w := moreio.ContextPipe(ctx)
+	defer r.Close()
+
+	errc := make(chan error, 1)
+	go func() {
+		_, err := io.WriteString(w, "Hello")
+		errc <- err
+	}()
+
+	// The Write is blocked because nothing is reading from the pipe.
+	cancel()
+	if err := <-errc; err != context.Canceled {
+		t.Errorf("Write after cancel = _, %v; want %v", err, context.Canceled)
+	}
+}
+
+func TestContextPipeCloseBeforeCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r, w := moreio.ContextPipe(ctx)
+
+	w.CloseWithError(errArbitrary)
+	cancel()
+
+	// The error from the earlier CloseWithError takes precedence.
+	if _, err := r.Read(make([]byte, 1)); err != errArbitrary {
+		t.Errorf("Read = _, %v; want errArbitrary", err)
+	}
+	r.Close()
+}
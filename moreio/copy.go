@@ -0,0 +1,95 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreio
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// CopyContext is like io.Copy, but stops copying and returns ctx.Err() when ctx
+// is done, along with the number of bytes copied so far.
+//
+// CopyContext checks ctx only between calls to src.Read; it cannot interrupt
+// a Read or Write that is already blocked. To bound the amount of data copied
+// between checks, use CopyContextBuffer.
+func CopyContext(ctx context.Context, dst io.Writer, src io.Reader) (written int64, err error) {
+	return CopyContextBuffer(ctx, dst, src, nil)
+}
+
+// CopyContextBuffer is like CopyContext, but reads in chunks of at most len(buf)
+// bytes, using buf as the intermediate buffer. If buf is nil, one is allocated;
+// otherwise, if it has zero length, CopyContextBuffer panics.
+func CopyContextBuffer(ctx context.Context, dst io.Writer, src io.Reader, buf []byte) (written int64, err error) {
+	if buf == nil {
+		buf = make([]byte, 32*1024)
+	} else if len(buf) == 0 {
+		panic("empty buffer in CopyContextBuffer")
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return written, err
+		}
+
+		nr, rerr := src.Read(buf)
+		if nr > 0 {
+			nw, werr := dst.Write(buf[:nr])
+			written += int64(nw)
+			if werr != nil {
+				return written, werr
+			}
+			if nw != nr {
+				return written, io.ErrShortWrite
+			}
+		}
+		if rerr == io.EOF {
+			return written, nil
+		}
+		if rerr != nil {
+			return written, rerr
+		}
+	}
+}
+
+// CopyPooled is like io.Copy, but if an intermediate buffer is needed (because
+// src does not implement io.WriterTo and dst does not implement
+// io.ReaderFrom), it obtains the buffer from pool and returns it to the pool
+// when the copy is complete.
+//
+// The values in pool must be of type []byte or *[]byte, with non-zero
+// capacity. If pool.Get returns nil, CopyPooled allocates a new buffer and
+// puts it in the pool afterward.
+func CopyPooled(dst io.Writer, src io.Reader, pool *sync.Pool) (written int64, err error) {
+	if _, ok := src.(io.WriterTo); ok {
+		return io.Copy(dst, src)
+	}
+	if _, ok := dst.(io.ReaderFrom); ok {
+		return io.Copy(dst, src)
+	}
+
+	v := pool.Get()
+	var buf []byte
+	switch v := v.(type) {
+	case nil:
+		buf = make([]byte, 32*1024)
+	case []byte:
+		buf = v
+	case *[]byte:
+		buf = *v
+	default:
+		panic("CopyPooled: pool contains a value that is neither []byte nor *[]byte")
+	}
+	if v == nil {
+		v = &buf
+	}
+	defer pool.Put(v)
+
+	if len(buf) == 0 {
+		buf = buf[:cap(buf)]
+	}
+	return io.CopyBuffer(dst, src, buf)
+}
@@ -0,0 +1,88 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreio_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bcmills/more/moreio"
+)
+
+func TestCopyContext(t *testing.T) {
+	in := strings.Repeat("Hello, moreio! ", 1000)
+	b := new(strings.Builder)
+	n, err := moreio.CopyContext(context.Background(), b, strings.NewReader(in))
+	if n != int64(len(in)) || err != nil {
+		t.Fatalf("CopyContext(…) = %v, %v; want %v, <nil>", n, err, len(in))
+	}
+	if b.String() != in {
+		t.Fatalf("output differs from input")
+	}
+}
+
+// A cancelingWriter cancels a context after N bytes have been written.
+type cancelingWriter struct {
+	strings.Builder
+	N      int
+	cancel context.CancelFunc
+}
+
+func (w *cancelingWriter) Write(p []byte) (int, error) {
+	n, err := w.Builder.Write(p)
+	if w.Len() >= w.N {
+		w.cancel()
+	}
+	return n, err
+}
+
+func TestCopyContextBufferCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &cancelingWriter{N: 10, cancel: cancel}
+
+	n, err := moreio.CopyContextBuffer(ctx, w, strings.NewReader("Hello, moreio!"), make([]byte, 4))
+	if n != 12 || err != context.Canceled {
+		t.Fatalf("CopyContextBuffer(…) = %v, %v; want 12, %v", n, err, context.Canceled)
+	}
+	if want := "Hello, morei"; w.String() != want {
+		t.Fatalf("output = %q; want %q", w.String(), want)
+	}
+}
+
+func TestCopyPooled(t *testing.T) {
+	gets := 0
+	pool := &sync.Pool{New: func() interface{} {
+		gets++
+		b := make([]byte, 16)
+		return &b
+	}}
+
+	in := strings.Repeat("Hello, moreio! ", 100)
+
+	// Neither the source nor the destination can copy without a buffer,
+	// so CopyPooled should obtain one from the pool.
+	b := new(strings.Builder)
+	n, err := moreio.CopyPooled(struct{ io.Writer }{b}, struct{ io.Reader }{strings.NewReader(in)}, pool)
+	if n != int64(len(in)) || err != nil || b.String() != in {
+		t.Fatalf("CopyPooled(…) = %v, %v; want %v, <nil>", n, err, len(in))
+	}
+	if gets != 1 {
+		t.Errorf("pool.New called %d times; want 1", gets)
+	}
+
+	// strings.Reader implements io.WriterTo, so no buffer is needed.
+	gets = 0
+	b.Reset()
+	n, err = moreio.CopyPooled(struct{ io.Writer }{b}, strings.NewReader(in), pool)
+	if n != int64(len(in)) || err != nil || b.String() != in {
+		t.Fatalf("CopyPooled(…) = %v, %v; want %v, <nil>", n, err, len(in))
+	}
+	if gets != 0 {
+		t.Errorf("pool.New called %d times; want 0", gets)
+	}
+}
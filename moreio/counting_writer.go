@@ -0,0 +1,38 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreio
+
+import (
+	"io"
+	"sync/atomic"
+)
+
+// A CountingWriter writes to W and counts the total number of bytes written in
+// N. Each call to Write atomically adds the number of bytes written to N.
+//
+// N must only be accessed atomically (for example, using the Count method)
+// while calls to Write may be in progress.
+type CountingWriter struct {
+	W io.Writer
+	N int64
+}
+
+// Count returns the number of bytes written so far.
+// It is safe to call concurrently with Write.
+func (cw *CountingWriter) Count() int64 {
+	return atomic.LoadInt64(&cw.N)
+}
+
+func (cw *CountingWriter) Write(p []byte) (n int, err error) {
+	n, err = cw.W.Write(p)
+	atomic.AddInt64(&cw.N, int64(n))
+	return n, err
+}
+
+func (cw *CountingWriter) WriteString(s string) (n int, err error) {
+	n, err = WriteString(cw.W, s)
+	atomic.AddInt64(&cw.N, int64(n))
+	return n, err
+}
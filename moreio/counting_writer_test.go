@@ -0,0 +1,35 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreio_test
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/bcmills/more/moreio"
+)
+
+func TestCountingWriterConcurrent(t *testing.T) {
+	w := &moreio.CountingWriter{W: io.Discard}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				fmt.Fprintf(w, "%03d", j)
+				io.WriteString(w, "!")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := w.Count(); n != 10*100*4 {
+		t.Fatalf("Count() = %v; want %v", n, 10*100*4)
+	}
+}
@@ -0,0 +1,19 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreio
+
+import (
+	"io"
+)
+
+// FailAfter returns a Writer that discards the data written to it, but accepts
+// only n bytes in total. Once n bytes have been written, Write accepts only the
+// bytes within the limit and returns their count along with err.
+//
+// FailAfter is intended for testing how callers handle short writes and
+// write errors. If err is nil, Write returns io.ErrShortWrite instead.
+func FailAfter(n int64, err error) io.Writer {
+	return &LimitedWriter{W: io.Discard, N: n, Err: err}
+}
@@ -0,0 +1,48 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreio_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/bcmills/more/moreio"
+)
+
+func TestFailAfter(t *testing.T) {
+	w := moreio.FailAfter(7, errArbitrary)
+
+	n, err := io.WriteString(w, "Hello")
+	if n != 5 || err != nil {
+		t.Fatalf(`WriteString("Hello") = %v, %v; want 5, <nil>`, n, err)
+	}
+
+	n, err = io.WriteString(w, ", world!")
+	if n != 2 || err != errArbitrary {
+		t.Fatalf(`WriteString(", world!") = %v, %v; want 2, errArbitrary`, n, err)
+	}
+
+	n, err = w.Write([]byte("!"))
+	if n != 0 || err != errArbitrary {
+		t.Fatalf(`Write("!") = %v, %v; want 0, errArbitrary`, n, err)
+	}
+}
+
+func TestFailAfterCopy(t *testing.T) {
+	// io.Copy uses the ReadFrom method of the returned Writer, if any,
+	// which must also inject the error.
+	injected := errors.New("injected")
+	for _, src := range []io.Reader{
+		strings.NewReader("Hello, world!"),
+		struct{ io.Reader }{strings.NewReader("Hello, world!")},
+	} {
+		n, err := io.Copy(moreio.FailAfter(5, injected), src)
+		if n != 5 || !errors.Is(err, injected) {
+			t.Errorf("io.Copy(FailAfter(5, injected), %T) = %v, %v; want 5, injected", src, n, err)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreio
+
+import (
+	"io"
+)
+
+// A LimitedReader reads from R but limits the amount of data returned to just N
+// bytes. Each call to Read updates N to reflect the new amount remaining.
+//
+// Read returns a customizable error (or io.EOF by default) when N <= 0, so
+// that callers can distinguish a truncated stream from the end of R.
+type LimitedReader struct {
+	R   io.Reader
+	N   int64
+	Err error // the error to return when N <= 0
+}
+
+// LimitReader returns a Reader that reads from r but stops with err
+// after n bytes. err must be non-nil.
+func LimitReader(r io.Reader, n int64, err error) *LimitedReader {
+	if err == nil {
+		panic("LimitReader: err must be non-nil")
+	}
+	return &LimitedReader{
+		R:   r,
+		N:   n,
+		Err: err,
+	}
+}
+
+func (lr *LimitedReader) err() error {
+	if lr.Err == nil {
+		return io.EOF
+	}
+	return lr.Err
+}
+
+func (lr *LimitedReader) Read(p []byte) (n int, err error) {
+	if lr.N <= 0 {
+		return 0, lr.err()
+	}
+
+	if int64(len(p)) > lr.N {
+		p = p[:lr.N]
+	}
+	n, err = lr.R.Read(p)
+	lr.N -= int64(n)
+	return n, err
+}
+
+func (lr *LimitedReader) ReadByte() (byte, error) {
+	if lr.N <= 0 {
+		return 0, lr.err()
+	}
+
+	c, err := ReadByte(lr.R)
+	if err != nil {
+		return 0, err
+	}
+	lr.N--
+	return c, nil
+}
@@ -0,0 +1,74 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreio_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/bcmills/more/moreio"
+)
+
+func TestLimitedReaderReadLimits(t *testing.T) {
+	r := moreio.LimitReader(strings.NewReader("Hello, moreio!"), 9, errArbitrary)
+	t.Logf(`r := moreio.LimitReader(strings.NewReader("Hello, moreio!"), 9, errArbitrary)`)
+
+	buf := make([]byte, 5)
+	n, err := r.Read(buf)
+	t.Logf(`r.Read(<5 bytes>) = %v, %v`, n, err)
+	if n != 5 || err != nil || string(buf[:n]) != "Hello" {
+		t.Fatalf(`want 5, <nil> ("Hello")`)
+	}
+
+	n, err = r.Read(buf)
+	t.Logf(`r.Read(<5 bytes>) = %v, %v`, n, err)
+	if n != 4 || err != nil || string(buf[:n]) != ", mo" {
+		t.Fatalf(`want 4, <nil> (", mo")`)
+	}
+
+	n, err = r.Read(buf)
+	t.Logf(`r.Read(<5 bytes>) = %v, %v`, n, err)
+	if n != 0 || err != errArbitrary {
+		t.Fatalf("want 0, errArbitrary")
+	}
+}
+
+func TestLimitedReaderUnderlyingEOF(t *testing.T) {
+	r := moreio.LimitReader(strings.NewReader("Hello"), 9, errArbitrary)
+	b, err := io.ReadAll(r)
+	if string(b) != "Hello" || err != nil {
+		t.Fatalf(`ReadAll(r) = %q, %v; want "Hello", <nil>`, b, err)
+	}
+	if r.N != 4 {
+		t.Fatalf("r.N = %v; want 4", r.N)
+	}
+}
+
+func TestLimitedReaderReadByte(t *testing.T) {
+	for _, src := range []io.Reader{
+		strings.NewReader("Hello"),
+		struct{ io.Reader }{strings.NewReader("Hello")}, // no ReadByte method
+	} {
+		r := moreio.LimitReader(src, 2, errArbitrary)
+		for _, want := range []byte("He") {
+			c, err := r.ReadByte()
+			if c != want || err != nil {
+				t.Fatalf("%T: ReadByte() = %q, %v; want %q, <nil>", src, c, err, want)
+			}
+		}
+		if c, err := r.ReadByte(); err != errArbitrary {
+			t.Fatalf("%T: ReadByte() = %q, %v; want errArbitrary", src, c, err)
+		}
+	}
+}
+
+func TestLimitedReaderZeroValue(t *testing.T) {
+	var r moreio.LimitedReader
+	n, err := r.Read(make([]byte, 5))
+	if n != 0 || err != io.EOF {
+		t.Fatalf(`Read(<5 bytes>) = %v, %v; want 0, EOF`, n, err)
+	}
+}
@@ -32,6 +32,19 @@ func LimitWriter(w io.Writer, n int64, err error) *LimitedWriter {
 	}
 }
 
+// LimitMultiWriter returns a Writer that duplicates its writes to all of the
+// provided writers, similar to io.MultiWriter, but stops with err after n bytes
+// have been written to each. err must be non-nil.
+//
+// If any of the provided writers returns an error, Write stops and returns that
+// error immediately.
+func LimitMultiWriter(n int64, err error, ws ...io.Writer) io.Writer {
+	if err == nil {
+		panic("LimitMultiWriter: err must be non-nil")
+	}
+	return LimitWriter(io.MultiWriter(ws...), n, err)
+}
+
 func (lw *LimitedWriter) err() error {
 	if lw.Err == nil {
 		return io.ErrShortWrite
@@ -77,6 +90,31 @@ func (lw *LimitedWriter) WriteString(s string) (n int, err error) {
 	return n, err
 }
 
+// ReadFrom copies data from r to lw.W until either io.EOF or the limit is
+// reached, using lw.W's ReadFrom method if it has one. As with Write, ReadFrom
+// returns the limit error if r has more data than the limit allows.
+//
+// To tell whether r has more data, ReadFrom reads one byte past the limit. That
+// read may block until r produces more data or reaches EOF, and the extra byte
+// (if any) is consumed from r and discarded.
+func (lw *LimitedWriter) ReadFrom(r io.Reader) (n int64, err error) {
+	if lw.N <= 0 {
+		return 0, lw.err()
+	}
+
+	n, err = io.Copy(lw.W, io.LimitReader(r, lw.N))
+	lw.N -= n
+	if lw.N <= 0 && err == nil {
+		// We can't tell whether the copy stopped due to the limit or a
+		// coincident EOF without trying to read past the limit.
+		var arr [1]byte
+		if m, _ := io.ReadFull(r, arr[:]); m > 0 {
+			err = lw.err()
+		}
+	}
+	return n, err
+}
+
 func (lw *LimitedWriter) WriteByte(c byte) error {
 	if lw.N <= 0 {
 		return lw.err()
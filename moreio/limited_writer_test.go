@@ -96,3 +96,79 @@ func TestLimitedWriterWriteStringZeroValue(t *testing.T) {
 		t.Fatalf(`WriteString("") = %v, %v; want 0, ErrShortWrite`, n, err)
 	}
 }
+
+// A readerFromRecorder records whether its ReadFrom method was called.
+type readerFromRecorder struct {
+	strings.Builder
+	called bool
+}
+
+func (w *readerFromRecorder) ReadFrom(r io.Reader) (int64, error) {
+	w.called = true
+	return io.Copy(&w.Builder, r)
+}
+
+func TestLimitedWriterReadFrom(t *testing.T) {
+	for _, tc := range []struct {
+		in      string
+		wantN   int64
+		wantErr error
+	}{
+		{in: "Hello", wantN: 5},
+		{in: "Hello, mo", wantN: 9},
+		{in: "Hello, moreio!", wantN: 9, wantErr: errArbitrary},
+	} {
+		b := new(readerFromRecorder)
+		w := moreio.LimitWriter(b, 9, errArbitrary)
+		// Hide the WriteTo method of strings.Reader so that io.Copy uses ReadFrom.
+		n, err := io.Copy(w, struct{ io.Reader }{strings.NewReader(tc.in)})
+		t.Logf("io.Copy(w, %q) = %v, %v", tc.in, n, err)
+		if n != tc.wantN || err != tc.wantErr {
+			t.Errorf("want %v, %v", tc.wantN, tc.wantErr)
+		}
+		if want := tc.in[:tc.wantN]; b.String() != want {
+			t.Errorf("output = %q; want %q", b.String(), want)
+		}
+		if !b.called {
+			t.Errorf("underlying ReadFrom method was not called")
+		}
+	}
+
+	// ReadFrom consumes exactly one byte beyond the limit to detect overflow.
+	r := strings.NewReader("Hello, moreio!")
+	w := moreio.LimitWriter(new(strings.Builder), 9, errArbitrary)
+	if n, err := w.ReadFrom(r); n != 9 || err != errArbitrary {
+		t.Errorf("ReadFrom(_) = %v, %v; want 9, errArbitrary", n, err)
+	}
+	if r.Len() != 4 {
+		t.Errorf("after ReadFrom, %d bytes remain unread; want 4", r.Len())
+	}
+	if n, err := w.ReadFrom(r); n != 0 || err != errArbitrary {
+		t.Errorf(`with N == 0: ReadFrom(_) = %v, %v; want 0, errArbitrary`, n, err)
+	}
+}
+
+func TestLimitMultiWriter(t *testing.T) {
+	b1 := new(strings.Builder)
+	b2 := new(strings.Builder)
+	w := moreio.LimitMultiWriter(9, errArbitrary, b1, b2)
+
+	n, err := io.WriteString(w, "Hello, moreio!")
+	t.Logf(`io.WriteString(w, "Hello, moreio!") = %v, %v`, n, err)
+	if n != 9 || err != errArbitrary {
+		t.Fatalf("want 9, errArbitrary")
+	}
+	for _, b := range []*strings.Builder{b1, b2} {
+		if b.String() != "Hello, mo" {
+			t.Errorf(`output = %q; want "Hello, mo"`, b.String())
+		}
+	}
+
+	errWrite := errors.New("write error")
+	w = moreio.LimitMultiWriter(9, errArbitrary, b1, moreio.LimitWriter(io.Discard, 2, errWrite))
+	n, err = io.WriteString(w, "Hello")
+	t.Logf(`io.WriteString(w, "Hello") = %v, %v`, n, err)
+	if err != errWrite {
+		t.Fatalf("want errWrite")
+	}
+}
@@ -6,9 +6,14 @@
 package moreio
 
 import (
+	"errors"
 	"io"
 )
 
+// ErrInvalidRune indicates that a rune to be written is not a valid Unicode
+// code point, and so has no UTF-8 encoding.
+var ErrInvalidRune = errors.New("moreio: invalid rune")
+
 func WriteByte(w io.Writer, c byte) error {
 	bw, ok := w.(io.ByteWriter)
 	if ok {
@@ -22,6 +27,19 @@ func WriteByte(w io.Writer, c byte) error {
 	return err
 }
 
+func WriteString(w io.Writer, s string) (n int, err error) {
+	sw, ok := w.(io.StringWriter)
+	if ok {
+		return sw.WriteString(s)
+	}
+
+	n, err = w.Write([]byte(s))
+	if n < len(s) && err == nil {
+		return n, io.ErrShortWrite
+	}
+	return n, err
+}
+
 const utfMax = 4 // equal to utf8.UTFMax, but without importing utf8.
 
 func WriteRune(w io.Writer, r rune) (n int, err error) {
@@ -40,3 +58,118 @@ func WriteRune(w io.Writer, r rune) (n int, err error) {
 	}
 	return n, err
 }
+
+// WriteValidRune is like WriteRune, but returns ErrInvalidRune (without writing
+// anything) if r is not a valid Unicode code point, instead of writing the
+// encoding of utf8.RuneError.
+func WriteValidRune(w io.Writer, r rune) (n int, err error) {
+	if !validRune(r) {
+		return 0, ErrInvalidRune
+	}
+	return WriteRune(w, r)
+}
+
+// validRune is equivalent to utf8.ValidRune.
+func validRune(r rune) bool {
+	switch {
+	case 0 <= r && r < 0xD800:
+		return true
+	case 0xDFFF < r && r <= 0x10FFFF:
+		return true
+	}
+	return false
+}
+
+func ReadByte(r io.Reader) (byte, error) {
+	br, ok := r.(io.ByteReader)
+	if ok {
+		return br.ReadByte()
+	}
+
+	var arr [1]byte
+	if _, err := io.ReadFull(r, arr[:]); err != nil {
+		return 0, err
+	}
+	return arr[0], nil
+}
+
+const runeError = '\uFFFD' // equal to utf8.RuneError
+
+// ReadRune reads a single UTF-8 encoded rune from r, returning the rune and its
+// size in bytes. If r does not implement io.RuneReader, ReadRune reads only as
+// many bytes as are needed to decode the rune.
+//
+// If the bytes read are not a valid UTF-8 encoding, ReadRune returns
+// utf8.RuneError and the number of bytes consumed. If an incomplete encoding is
+// followed by a byte that cannot continue it, that byte is not part of the
+// invalid encoding: if r implements io.ByteScanner, ReadRune unreads it so that
+// the next read returns it, but otherwise it is consumed and lost.
+func ReadRune(r io.Reader) (rune, int, error) {
+	rr, ok := r.(io.RuneReader)
+	if ok {
+		return rr.ReadRune()
+	}
+
+	var arr [utfMax]byte
+	c, err := ReadByte(r)
+	if err != nil {
+		return 0, 0, err
+	}
+	arr[0] = c
+	size := 1
+
+	for want := encodedLen(c); size < want; {
+		c, err := ReadByte(r)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			// Report the incomplete encoding now and the EOF on the next call.
+			return runeError, size, nil
+		} else if err != nil {
+			return runeError, size, err
+		}
+		if c&0xC0 != 0x80 {
+			// c is not a continuation byte, so the encoding is invalid.
+			if bs, ok := r.(io.ByteScanner); ok && bs.UnreadByte() == nil {
+				return runeError, size, nil
+			}
+			return runeError, size + 1, nil
+		}
+		arr[size] = c
+		size++
+	}
+
+	return decodeRune(string(arr[:size])), size, nil
+}
+
+// encodedLen returns the length of the UTF-8 encoding that starts with byte c,
+// or 1 if c cannot start a multi-byte encoding.
+func encodedLen(c byte) int {
+	switch {
+	case c < 0xC0:
+		return 1
+	case c < 0xE0:
+		return 2
+	case c < 0xF0:
+		return 3
+	case c < 0xF8:
+		return 4
+	default:
+		return 1
+	}
+}
+
+// decodeRune returns the rune encoded in s,
+// or utf8.RuneError if s is not exactly one validly-encoded rune.
+func decodeRune(s string) rune {
+	r, width := runeError, 0
+	for i, c := range s {
+		if i > 0 {
+			width = i
+			break
+		}
+		r, width = c, len(s)
+	}
+	if width != len(s) {
+		return runeError
+	}
+	return r
+}
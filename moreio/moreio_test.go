@@ -0,0 +1,171 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreio_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/bcmills/more/moreio"
+)
+
+// A shortWriter accepts at most N bytes per call to Write, without error.
+type shortWriter struct {
+	strings.Builder
+	N int
+}
+
+func (w *shortWriter) Write(p []byte) (int, error) {
+	if len(p) > w.N {
+		p = p[:w.N]
+	}
+	return w.Builder.Write(p)
+}
+
+func TestWriteString(t *testing.T) {
+	b := new(strings.Builder)
+	n, err := moreio.WriteString(b, "Hello")
+	if n != 5 || err != nil || b.String() != "Hello" {
+		t.Fatalf(`WriteString(b, "Hello") = %v, %v; want 5, <nil>`, n, err)
+	}
+
+	w := &shortWriter{N: 3}
+	n, err = moreio.WriteString(struct{ io.Writer }{w}, "Hello")
+	if n != 3 || err != io.ErrShortWrite {
+		t.Fatalf(`WriteString(w, "Hello") = %v, %v; want 3, ErrShortWrite`, n, err)
+	}
+}
+
+// A byteReader returns at most one byte per call to Read,
+// and does not implement any other methods.
+type byteReader struct {
+	r io.Reader
+}
+
+func (r byteReader) Read(p []byte) (int, error) {
+	if len(p) > 1 {
+		p = p[:1]
+	}
+	return r.r.Read(p)
+}
+
+// A byteScanner implements io.ByteScanner, but not io.RuneReader.
+type byteScanner struct {
+	r *strings.Reader
+}
+
+func (r byteScanner) Read(p []byte) (int, error) { return r.r.Read(p) }
+func (r byteScanner) ReadByte() (byte, error)    { return r.r.ReadByte() }
+func (r byteScanner) UnreadByte() error          { return r.r.UnreadByte() }
+
+func TestReadByte(t *testing.T) {
+	for _, r := range []io.Reader{
+		strings.NewReader("Hi"),
+		byteReader{strings.NewReader("Hi")},
+	} {
+		for _, want := range []byte("Hi") {
+			c, err := moreio.ReadByte(r)
+			if c != want || err != nil {
+				t.Fatalf("%T: ReadByte() = %q, %v; want %q, <nil>", r, c, err, want)
+			}
+		}
+		if c, err := moreio.ReadByte(r); err != io.EOF {
+			t.Fatalf("%T: ReadByte() = %q, %v; want EOF", r, c, err)
+		}
+	}
+}
+
+func TestReadRune(t *testing.T) {
+	type result struct {
+		r    rune
+		size int
+		err  error
+	}
+	for _, tc := range []struct {
+		in   string
+		want []result
+	}{
+		{
+			in: "aé世🙂",
+			want: []result{
+				{'a', 1, nil},
+				{'é', 2, nil},
+				{'世', 3, nil},
+				{'🙂', 4, nil},
+				{0, 0, io.EOF},
+			},
+		},
+		{
+			in: "\xffa\xe4\xb8x",
+			want: []result{
+				{'�', 1, nil},
+				{'a', 1, nil},
+				{'�', 2, nil},
+				{'x', 1, nil},
+				{0, 0, io.EOF},
+			},
+		},
+		{
+			in: "\xf0\x9f",
+			want: []result{
+				{'�', 2, nil},
+				{0, 0, io.EOF},
+			},
+		},
+	} {
+		r := byteScanner{strings.NewReader(tc.in)}
+		for _, want := range tc.want {
+			c, size, err := moreio.ReadRune(r)
+			if c != want.r || size != want.size || err != want.err {
+				t.Fatalf("%q: ReadRune() = %q, %v, %v; want %q, %v, %v", tc.in, c, size, err, want.r, want.size, want.err)
+			}
+		}
+	}
+}
+
+func TestReadRuneNotByteScanner(t *testing.T) {
+	// Without UnreadByte, the byte that terminates an incomplete encoding
+	// cannot be pushed back, so it is reported as part of the invalid rune.
+	r := byteReader{strings.NewReader("\xe4\xb8xy")}
+	for _, want := range []struct {
+		r    rune
+		size int
+		err  error
+	}{
+		{'�', 3, nil},
+		{'y', 1, nil},
+		{0, 0, io.EOF},
+	} {
+		c, size, err := moreio.ReadRune(r)
+		if c != want.r || size != want.size || err != want.err {
+			t.Fatalf("ReadRune() = %q, %v, %v; want %q, %v, %v", c, size, err, want.r, want.size, want.err)
+		}
+	}
+}
+
+func TestWriteValidRune(t *testing.T) {
+	for _, tc := range []struct {
+		r       rune
+		wantN   int
+		wantErr error
+	}{
+		{'a', 1, nil},
+		{'世', 3, nil},
+		{'�', 3, nil},
+		{0xD800, 0, moreio.ErrInvalidRune},
+		{0x110000, 0, moreio.ErrInvalidRune},
+		{-1, 0, moreio.ErrInvalidRune},
+	} {
+		b := new(strings.Builder)
+		n, err := moreio.WriteValidRune(b, tc.r)
+		if n != tc.wantN || err != tc.wantErr {
+			t.Errorf("WriteValidRune(b, %U) = %v, %v; want %v, %v", tc.r, n, err, tc.wantN, tc.wantErr)
+		}
+		if err == nil && b.String() != string(tc.r) {
+			t.Errorf("WriteValidRune(b, %U) wrote %q", tc.r, b.String())
+		}
+	}
+}
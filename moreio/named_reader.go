@@ -0,0 +1,84 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreio
+
+import (
+	"fmt"
+	"io"
+)
+
+// A NamedReader is a source of data for NamedMultiReader, labeled with a name
+// for use in error messages.
+//
+// If R is nil, Open is called to obtain the Reader when it is first needed.
+// If the Reader returned by Open is also an io.Closer, it is closed once it has
+// been read to EOF.
+type NamedReader struct {
+	Name string
+	R    io.Reader
+	Open func() (io.Reader, error)
+}
+
+// NamedMultiReader returns a Reader that is the logical concatenation of the
+// provided sources, like io.MultiReader. If reading from (or opening or
+// closing) a source fails, the returned error wraps the original error and
+// identifies the source by name.
+//
+// Once an error other than io.EOF has occurred, all subsequent reads return
+// the same error.
+func NamedMultiReader(srcs ...NamedReader) io.Reader {
+	return &namedMultiReader{srcs: append([]NamedReader(nil), srcs...)}
+}
+
+type namedMultiReader struct {
+	srcs   []NamedReader
+	opened bool // whether srcs[0].R was obtained from srcs[0].Open
+	err    error
+}
+
+func (mr *namedMultiReader) Read(p []byte) (n int, err error) {
+	if mr.err != nil {
+		return 0, mr.err
+	}
+
+	for len(mr.srcs) > 0 {
+		src := &mr.srcs[0]
+		if src.R == nil && src.Open != nil {
+			r, err := src.Open()
+			if err != nil {
+				mr.err = fmt.Errorf("moreio: opening %q: %w", src.Name, err)
+				return 0, mr.err
+			}
+			src.R = r
+			mr.opened = true
+		}
+
+		if src.R != nil {
+			n, err = src.R.Read(p)
+		} else {
+			err = io.EOF
+		}
+		if err != io.EOF {
+			if err != nil {
+				mr.err = fmt.Errorf("moreio: reading %q: %w", src.Name, err)
+				err = mr.err
+			}
+			return n, err
+		}
+
+		if c, ok := src.R.(io.Closer); ok && mr.opened {
+			if err := c.Close(); err != nil {
+				mr.err = fmt.Errorf("moreio: closing %q: %w", src.Name, err)
+				return n, mr.err
+			}
+		}
+		mr.srcs = mr.srcs[1:]
+		mr.opened = false
+		if n > 0 {
+			return n, nil
+		}
+	}
+	return 0, io.EOF
+}
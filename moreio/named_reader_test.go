@@ -0,0 +1,59 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreio_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/bcmills/more/moreio"
+)
+
+func TestNamedMultiReader(t *testing.T) {
+	opened := false
+	r := moreio.NamedMultiReader(
+		moreio.NamedReader{Name: "part1", R: strings.NewReader("Hello, ")},
+		moreio.NamedReader{Name: "part2", Open: func() (io.Reader, error) {
+			opened = true
+			return io.NopCloser(strings.NewReader("world!")), nil
+		}},
+	)
+
+	b := make([]byte, 7)
+	if _, err := io.ReadFull(r, b); err != nil {
+		t.Fatal(err)
+	}
+	if opened {
+		t.Errorf("part2 opened before it was needed")
+	}
+
+	rest, err := io.ReadAll(r)
+	if err != nil || string(b)+string(rest) != "Hello, world!" {
+		t.Fatalf("contents = %q, %v; want %q, <nil>", string(b)+string(rest), err, "Hello, world!")
+	}
+}
+
+func TestNamedMultiReaderError(t *testing.T) {
+	r := moreio.NamedMultiReader(
+		moreio.NamedReader{Name: "part1", R: strings.NewReader("Hello, ")},
+		moreio.NamedReader{Name: "part2", R: iotest.ErrReader(errArbitrary)},
+	)
+
+	b, err := io.ReadAll(r)
+	t.Logf("ReadAll: %q, %v", b, err)
+	if string(b) != "Hello, " || !errors.Is(err, errArbitrary) || !strings.Contains(err.Error(), `"part2"`) {
+		t.Fatalf(`ReadAll = %q, %v; want "Hello, " and an error mentioning "part2"`, b, err)
+	}
+
+	r = moreio.NamedMultiReader(moreio.NamedReader{Name: "missing", Open: func() (io.Reader, error) {
+		return nil, errArbitrary
+	}})
+	if _, err := r.Read(make([]byte, 1)); !errors.Is(err, errArbitrary) || !strings.Contains(err.Error(), `"missing"`) {
+		t.Fatalf(`Read error = %v; want an error mentioning "missing"`, err)
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreio
+
+import (
+	"io"
+	"sync"
+)
+
+// An OffsetRecordingWriter writes to W and records the offset, relative to the
+// first write, at which each call to Write or WriteString began.
+//
+// The offsets are useful for indexing the boundaries of records as they are
+// written to a stream, without a second pass over the output.
+//
+// Calls to Write and WriteString must not be made concurrently with each other,
+// but Offsets may be called concurrently with either.
+type OffsetRecordingWriter struct {
+	W io.Writer
+
+	mu      sync.Mutex
+	n       int64
+	offsets []int64
+}
+
+// Offsets returns the offsets at which each call to Write or WriteString began,
+// in the order in which the calls were made.
+//
+// The returned slice is a copy, and is not modified by subsequent writes.
+func (ow *OffsetRecordingWriter) Offsets() []int64 {
+	ow.mu.Lock()
+	defer ow.mu.Unlock()
+	return append([]int64(nil), ow.offsets...)
+}
+
+func (ow *OffsetRecordingWriter) start() {
+	ow.mu.Lock()
+	ow.offsets = append(ow.offsets, ow.n)
+	ow.mu.Unlock()
+}
+
+func (ow *OffsetRecordingWriter) advance(n int) {
+	ow.mu.Lock()
+	ow.n += int64(n)
+	ow.mu.Unlock()
+}
+
+func (ow *OffsetRecordingWriter) Write(p []byte) (n int, err error) {
+	ow.start()
+	n, err = ow.W.Write(p)
+	ow.advance(n)
+	return n, err
+}
+
+func (ow *OffsetRecordingWriter) WriteString(s string) (n int, err error) {
+	ow.start()
+	n, err = WriteString(ow.W, s)
+	ow.advance(n)
+	return n, err
+}
@@ -0,0 +1,59 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreio_test
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bcmills/more/moreio"
+)
+
+func TestOffsetRecordingWriter(t *testing.T) {
+	var b strings.Builder
+	w := &moreio.OffsetRecordingWriter{W: &b}
+
+	if offsets := w.Offsets(); len(offsets) != 0 {
+		t.Fatalf("Offsets() before any writes = %v; want []", offsets)
+	}
+
+	io.WriteString(w, "Hello")
+	w.Write([]byte(", "))
+	w.Write(nil)
+	io.WriteString(w, "world!")
+
+	want := []int64{0, 5, 7, 7}
+	offsets := w.Offsets()
+	if !reflect.DeepEqual(offsets, want) {
+		t.Fatalf("Offsets() = %v; want %v", offsets, want)
+	}
+	if got := b.String(); got != "Hello, world!" {
+		t.Fatalf("wrote %q; want %q", got, "Hello, world!")
+	}
+
+	// The returned slice must be a snapshot, unaffected by later writes or by
+	// modifications from the caller.
+	offsets[0] = -1
+	io.WriteString(w, "\n")
+	if got := w.Offsets(); !reflect.DeepEqual(got, append(want, 13)) {
+		t.Fatalf("Offsets() = %v; want %v", got, append(want, 13))
+	}
+}
+
+func TestOffsetRecordingWriterShortWrite(t *testing.T) {
+	w := &moreio.OffsetRecordingWriter{W: moreio.FailAfter(3, errArbitrary)}
+
+	if _, err := io.WriteString(w, "Hello"); err != errArbitrary {
+		t.Fatalf("WriteString(\"Hello\") = _, %v; want errArbitrary", err)
+	}
+	io.WriteString(w, "!")
+
+	want := []int64{0, 3}
+	if got := w.Offsets(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Offsets() = %v; want %v", got, want)
+	}
+}
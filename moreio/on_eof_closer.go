@@ -0,0 +1,64 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreio
+
+import (
+	"errors"
+	"io"
+)
+
+// OnEOFCloser returns a ReadCloser that reads from r and calls closeFn as soon
+// as r returns io.EOF, so that a caller that reads to the end (for example,
+// using io.Copy) releases the underlying resource even if it never calls Close.
+//
+// closeFn is called at most once: Close calls it if it has not already been
+// called, and otherwise returns the error (if any) from the earlier call. If
+// closeFn returns a non-nil error at EOF, Read returns that error in place of
+// io.EOF.
+//
+// After closeFn has been called, Read does not call r again: it returns io.EOF
+// if r was read to the end, or an error if the reader was closed before then.
+func OnEOFCloser(r io.Reader, closeFn func() error) io.ReadCloser {
+	return &onEOFCloser{r: r, closeFn: closeFn}
+}
+
+var errOnEOFCloserClosed = errors.New("moreio: read from closed OnEOFCloser")
+
+type onEOFCloser struct {
+	r        io.Reader
+	closeFn  func() error
+	closed   bool
+	atEOF    bool
+	closeErr error
+}
+
+func (oc *onEOFCloser) Read(p []byte) (n int, err error) {
+	if oc.closed {
+		if !oc.atEOF {
+			return 0, errOnEOFCloserClosed
+		}
+		if oc.closeErr != nil {
+			return 0, oc.closeErr
+		}
+		return 0, io.EOF
+	}
+
+	n, err = oc.r.Read(p)
+	if err == io.EOF {
+		oc.atEOF = true
+		if cerr := oc.Close(); cerr != nil {
+			err = cerr
+		}
+	}
+	return n, err
+}
+
+func (oc *onEOFCloser) Close() error {
+	if !oc.closed {
+		oc.closed = true
+		oc.closeErr = oc.closeFn()
+	}
+	return oc.closeErr
+}
@@ -0,0 +1,85 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreio_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/bcmills/more/moreio"
+)
+
+func TestOnEOFCloser(t *testing.T) {
+	closes := 0
+	rc := moreio.OnEOFCloser(strings.NewReader("Hello, world!"), func() error {
+		closes++
+		return nil
+	})
+
+	b := make([]byte, 5)
+	if _, err := io.ReadFull(rc, b); err != nil {
+		t.Fatal(err)
+	}
+	if closes != 0 {
+		t.Fatalf("closeFn called %d times before EOF; want 0", closes)
+	}
+
+	var sb strings.Builder
+	if _, err := io.Copy(&sb, rc); err != nil {
+		t.Fatal(err)
+	}
+	if got := sb.String(); got != ", world!" {
+		t.Errorf("read %q; want %q", got, ", world!")
+	}
+	if closes != 1 {
+		t.Fatalf("closeFn called %d times after EOF; want 1", closes)
+	}
+
+	if n, err := rc.Read(b); n != 0 || err != io.EOF {
+		t.Errorf("Read after EOF = %v, %v; want 0, EOF", n, err)
+	}
+	if err := rc.Close(); err != nil {
+		t.Errorf("Close after EOF = %v; want <nil>", err)
+	}
+	if closes != 1 {
+		t.Errorf("closeFn called %d times after Close; want 1", closes)
+	}
+}
+
+func TestOnEOFCloserEarlyClose(t *testing.T) {
+	closes := 0
+	rc := moreio.OnEOFCloser(strings.NewReader("Hello, world!"), func() error {
+		closes++
+		return errArbitrary
+	})
+
+	if err := rc.Close(); err != errArbitrary {
+		t.Errorf("Close() = %v; want errArbitrary", err)
+	}
+	if err := rc.Close(); err != errArbitrary {
+		t.Errorf("second Close() = %v; want errArbitrary", err)
+	}
+	if closes != 1 {
+		t.Errorf("closeFn called %d times; want 1", closes)
+	}
+
+	if n, err := rc.Read(make([]byte, 5)); n != 0 || err == nil || err == io.EOF {
+		t.Errorf("Read after Close = %v, %v; want 0, non-EOF error", n, err)
+	}
+}
+
+func TestOnEOFCloserError(t *testing.T) {
+	rc := moreio.OnEOFCloser(strings.NewReader("Hello"), func() error {
+		return errArbitrary
+	})
+
+	if _, err := io.Copy(io.Discard, rc); err != errArbitrary {
+		t.Errorf("io.Copy: err = %v; want errArbitrary", err)
+	}
+	if err := rc.Close(); err != errArbitrary {
+		t.Errorf("Close() = %v; want errArbitrary", err)
+	}
+}
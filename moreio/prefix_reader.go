@@ -0,0 +1,47 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreio
+
+import (
+	"io"
+)
+
+// PrefixReader returns a Reader that reads the bytes of prefix, followed by the
+// data from r. It is equivalent to io.MultiReader(bytes.NewReader(prefix), r),
+// but requires fewer allocations.
+//
+// PrefixReader is useful for sniffing a stream: after reading the first few
+// bytes of r to detect its format, a caller can pass the stream as a whole
+// (including the consumed bytes) to a parser.
+//
+// The returned Reader also implements io.ByteReader, so that a bufio.Reader or
+// other consumer can read single bytes without an intermediate buffer.
+// The caller must not modify prefix until all of it has been read.
+func PrefixReader(prefix []byte, r io.Reader) io.Reader {
+	return &prefixReader{prefix: prefix, r: r}
+}
+
+type prefixReader struct {
+	prefix []byte
+	r      io.Reader
+}
+
+func (pr *prefixReader) Read(p []byte) (n int, err error) {
+	if len(pr.prefix) > 0 {
+		n = copy(p, pr.prefix)
+		pr.prefix = pr.prefix[n:]
+		return n, nil
+	}
+	return pr.r.Read(p)
+}
+
+func (pr *prefixReader) ReadByte() (byte, error) {
+	if len(pr.prefix) > 0 {
+		c := pr.prefix[0]
+		pr.prefix = pr.prefix[1:]
+		return c, nil
+	}
+	return ReadByte(pr.r)
+}
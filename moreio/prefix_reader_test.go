@@ -0,0 +1,52 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreio_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/bcmills/more/moreio"
+)
+
+func TestPrefixReader(t *testing.T) {
+	src := strings.NewReader("Hello, world!")
+
+	// Sniff the first few bytes of the stream.
+	prefix := make([]byte, 5)
+	if _, err := io.ReadFull(src, prefix); err != nil {
+		t.Fatal(err)
+	}
+
+	r := moreio.PrefixReader(prefix, src)
+	if err := iotest.TestReader(r, []byte("Hello, world!")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPrefixReaderByte(t *testing.T) {
+	r := moreio.PrefixReader([]byte("He"), iotest.OneByteReader(strings.NewReader("llo")))
+	br, ok := r.(io.ByteReader)
+	if !ok {
+		t.Fatalf("PrefixReader does not implement io.ByteReader")
+	}
+
+	var sb strings.Builder
+	for {
+		c, err := br.ReadByte()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		sb.WriteByte(c)
+	}
+	if got := sb.String(); got != "Hello" {
+		t.Errorf("read %q; want %q", got, "Hello")
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreio
+
+import (
+	"io"
+)
+
+// SkipWriter returns a Writer that silently discards the first skip bytes
+// written to it, then writes the remaining bytes to w.
+//
+// Each call to Write reports the discarded bytes as written, so a Write that
+// is entirely discarded returns len(p), nil.
+func SkipWriter(w io.Writer, skip int64) io.Writer {
+	return &skipWriter{w: w, skip: skip}
+}
+
+type skipWriter struct {
+	w    io.Writer
+	skip int64
+}
+
+func (sw *skipWriter) Write(p []byte) (n int, err error) {
+	if sw.skip > 0 {
+		if int64(len(p)) <= sw.skip {
+			sw.skip -= int64(len(p))
+			return len(p), nil
+		}
+		n = int(sw.skip)
+		p = p[n:]
+		sw.skip = 0
+	}
+	m, err := sw.w.Write(p)
+	return n + m, err
+}
@@ -0,0 +1,48 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreio_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/bcmills/more/moreio"
+)
+
+func TestSkipWriter(t *testing.T) {
+	buf := new(strings.Builder)
+	w := moreio.SkipWriter(buf, 7)
+
+	n, err := io.WriteString(w, "Hello")
+	if n != 5 || err != nil || buf.Len() != 0 {
+		t.Fatalf(`WriteString("Hello") = %v, %v, output %q; want 5, <nil>, ""`, n, err, buf)
+	}
+
+	// This write straddles the boundary of the skipped prefix.
+	n, err = io.WriteString(w, ", world")
+	if n != 7 || err != nil || buf.String() != "world" {
+		t.Fatalf(`WriteString(", world") = %v, %v, output %q; want 7, <nil>, "world"`, n, err, buf)
+	}
+
+	n, err = io.WriteString(w, "!")
+	if n != 1 || err != nil || buf.String() != "world!" {
+		t.Fatalf(`WriteString("!") = %v, %v, output %q; want 1, <nil>, "world!"`, n, err, buf)
+	}
+}
+
+func TestSkipWriterError(t *testing.T) {
+	w := moreio.SkipWriter(moreio.FailAfter(2, errArbitrary), 3)
+
+	n, err := io.WriteString(w, "Hello")
+	if n != 5 || err != nil {
+		t.Fatalf(`WriteString("Hello") = %v, %v; want 5, <nil>`, n, err)
+	}
+
+	n, err = io.WriteString(w, "!")
+	if n != 0 || err != errArbitrary {
+		t.Fatalf(`WriteString("!") = %v, %v; want 0, errArbitrary`, n, err)
+	}
+}
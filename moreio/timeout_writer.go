@@ -0,0 +1,94 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreio
+
+import (
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// TimeoutWriter returns a Writer that writes to w, but abandons any Write that
+// does not complete within perWrite and returns os.ErrDeadlineExceeded for it,
+// like a Write to an os.File or net.Conn whose write deadline has passed.
+// TimeoutWriter is intended for writers that do not support SetWriteDeadline.
+//
+// Each Write to w is made in a separate goroutine using a copy of the data.
+// If a Write times out, that goroutine continues until the Write to w returns,
+// and it is not known how much of the data w eventually accepts. Subsequent
+// Writes wait for the abandoned Write to complete, and are subject to their own
+// timeout while doing so, so that only one Write to w is in progress at a
+// time. If w blocks forever, its abandoned goroutine is leaked.
+//
+// If a Write to w fails, the TimeoutWriter returns that error from all
+// subsequent calls to Write.
+func TimeoutWriter(w io.Writer, perWrite time.Duration) io.Writer {
+	if perWrite <= 0 {
+		panic("TimeoutWriter: perWrite must be positive")
+	}
+	return &timeoutWriter{
+		w:   w,
+		d:   perWrite,
+		sem: make(chan struct{}, 1),
+	}
+}
+
+type timeoutWriter struct {
+	w   io.Writer
+	d   time.Duration
+	sem chan struct{} // holds a token while a Write to w is in progress
+
+	mu  sync.Mutex
+	err error
+}
+
+type writeResult struct {
+	n   int
+	err error
+}
+
+func (tw *timeoutWriter) Write(p []byte) (n int, err error) {
+	timer := time.NewTimer(tw.d)
+	defer timer.Stop()
+
+	select {
+	case tw.sem <- struct{}{}:
+	case <-timer.C:
+		return 0, os.ErrDeadlineExceeded
+	}
+
+	tw.mu.Lock()
+	err = tw.err
+	tw.mu.Unlock()
+	if err != nil {
+		<-tw.sem
+		return 0, err
+	}
+
+	// The goroutine may outlive this call, so it must not retain p.
+	buf := append([]byte(nil), p...)
+	c := make(chan writeResult, 1)
+	go func() {
+		n, err := tw.w.Write(buf)
+		if err == nil && n < len(buf) {
+			err = io.ErrShortWrite
+		}
+		if err != nil {
+			tw.mu.Lock()
+			tw.err = err
+			tw.mu.Unlock()
+		}
+		<-tw.sem
+		c <- writeResult{n, err}
+	}()
+
+	select {
+	case r := <-c:
+		return r.n, r.err
+	case <-timer.C:
+		return 0, os.ErrDeadlineExceeded
+	}
+}
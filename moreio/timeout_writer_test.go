@@ -0,0 +1,91 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreio_test
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bcmills/more/moreio"
+)
+
+// blockingWriter is a Writer whose writes block until unblocked.
+type blockingWriter struct {
+	unblock chan struct{}
+	b       strings.Builder
+}
+
+func (bw *blockingWriter) Write(p []byte) (int, error) {
+	<-bw.unblock
+	return bw.b.Write(p)
+}
+
+func TestTimeoutWriter(t *testing.T) {
+	var b strings.Builder
+	w := moreio.TimeoutWriter(&b, 1*time.Minute)
+
+	io.WriteString(w, "Hello, ")
+	io.WriteString(w, "world!")
+	if got := b.String(); got != "Hello, world!" {
+		t.Errorf("wrote %q; want %q", got, "Hello, world!")
+	}
+}
+
+func TestTimeoutWriterSlow(t *testing.T) {
+	bw := &blockingWriter{unblock: make(chan struct{})}
+	w := moreio.TimeoutWriter(bw, 10*time.Millisecond)
+
+	p := []byte("Hello")
+	n, err := w.Write(p)
+	if n != 0 || !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Fatalf("Write to blocked writer = %v, %v; want 0, %v", n, err, os.ErrDeadlineExceeded)
+	}
+	// The abandoned write must not retain p.
+	copy(p, "XXXXX")
+
+	// The abandoned write is still in progress, so the next write must also
+	// time out instead of calling Write concurrently.
+	n, err = io.WriteString(w, ", world!")
+	if n != 0 || !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Fatalf("second Write to blocked writer = %v, %v; want 0, %v", n, err, os.ErrDeadlineExceeded)
+	}
+
+	close(bw.unblock)
+
+	// Once the abandoned writes complete, subsequent writes can proceed.
+	// Retry in case the test is running slowly enough to time out again.
+	for start := time.Now(); ; {
+		_, err = io.WriteString(w, "!")
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, os.ErrDeadlineExceeded) || time.Since(start) > 10*time.Second {
+			t.Fatalf("Write after unblocking = _, %v; want <nil>", err)
+		}
+	}
+
+	// The second write timed out before it started, so only the first write
+	// (unaffected by the caller reusing its buffer) reached bw before the last.
+	if got := bw.b.String(); got != "Hello!" {
+		t.Errorf("wrote %q; want %q", got, "Hello!")
+	}
+}
+
+func TestTimeoutWriterError(t *testing.T) {
+	w := moreio.TimeoutWriter(moreio.FailAfter(3, errArbitrary), 1*time.Minute)
+
+	n, err := io.WriteString(w, "Hello")
+	if n != 3 || err != errArbitrary {
+		t.Fatalf(`WriteString("Hello") = %v, %v; want 3, errArbitrary`, n, err)
+	}
+	n, err = io.WriteString(w, "!")
+	if n != 0 || err != errArbitrary {
+		t.Fatalf(`WriteString("!") = %v, %v; want 0, errArbitrary`, n, err)
+	}
+}
@@ -2,6 +2,13 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
+// Package moreexec provides a Cmd type that wraps exec.Cmd with more control
+// over how a command is interrupted and how long to wait for its I/O.
+//
+// Note that unlike an exec.Cmd, a Cmd whose Context has a deadline and whose
+// WaitDelay is zero does not wait indefinitely for its I/O pipes to be closed:
+// it closes them DeadlineWaitDelay after the command exits or the Context is
+// done. Set WaitDelay to a negative value to read the pipes until EOF instead.
 package moreexec
 
 import (
@@ -14,9 +21,12 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
+
+	"github.com/bcmills/more/sync/moreatomic"
 )
 
 // QuitSignal is syscall.SIGQUIT if it is defined and supported, or nil otherwise.
@@ -24,6 +34,19 @@ var QuitSignal os.Signal = quitSignal
 
 var ErrWaitDelay = errors.New("moreexec: WaitDelay expired before I/O complete")
 
+// LookPath is the function used by Command and CommandContext to resolve a
+// command name that contains no path separators to the path of an executable.
+// It defaults to exec.LookPath.
+//
+// A program (or test) that resolves executables by some other means, such as
+// from a hermetic toolchain, may replace LookPath before calling Command.
+// LookPath must not be modified concurrently with any call to Command.
+var LookPath = exec.LookPath
+
+// DeadlineWaitDelay is the effective WaitDelay of a Cmd whose WaitDelay field
+// is zero and whose Context has a deadline.
+const DeadlineWaitDelay = 1 * time.Second
+
 // A Cmd is like an exec.Cmd, but with additional fields as proposed in
 // https://go.dev/issue/50436.
 type Cmd struct {
@@ -55,25 +78,141 @@ type Cmd struct {
 	// (and return a non-nil error).
 	Interrupt os.Signal
 
+	// If Cancel is non-nil, Context must also be non-nil and Cancel will be
+	// called (instead of sending the Interrupt signal) when Context is done.
+	// Cancel may take any action needed to stop the command, such as writing to
+	// its stdin or signaling its process group.
+	//
+	// If Cancel returns nil and the command then exits with a success code,
+	// Wait and similar methods will return Context.Err() instead of nil.
+	// If Cancel returns an error other than one wrapping os.ErrProcessDone,
+	// Wait and similar methods will return that error (wrapped) if the command
+	// otherwise succeeds.
+	//
+	// If WaitDelay is also non-zero, the WaitDelay timer starts when Cancel is
+	// called, and the process is killed with os.Kill if it has not exited by
+	// the time the timer expires.
+	Cancel func() error
+
 	// If WaitDelay is non-zero, the command's I/O pipes will be closed after
 	// WaitDelay has elapsed after either the command's process has exited or
 	// (if Context is non-nil) Context is done, whichever occurs first.
 	// If the command's process is still running after WaitDelay has elapsed,
 	// it will be terminated with os.Kill before the pipes are closed.
+	// After the pipes are closed, Wait no longer waits for the goroutine copying
+	// from Stdin (if any), which may remain blocked in a call to Stdin.Read.
 	//
 	// If the command exits with a success code after pipes are closed due to
-	// WaitDelay and no Interrupt signal has been sent, Wait and similar methods
-	// will return ErrWaitDelay instead of nil.
+	// WaitDelay and neither an Interrupt signal nor Cancel has been sent, Wait
+	// and similar methods will return ErrWaitDelay instead of nil.
 	//
-	// If WaitDelay is zero (the default), I/O pipes will be read until EOF,
-	// which might not occur until orphaned subprocesses of the command have
-	// also closed their descriptors for the pipes.
+	// If WaitDelay is zero (the default) and Context has a deadline, the
+	// command uses a WaitDelay of DeadlineWaitDelay: a short, fixed grace period
+	// after the process exits or Context is done. That bounds the wait for a
+	// command canceled at its deadline.
+	//
+	// That is a change in behavior from exec.Cmd, for which a zero WaitDelay
+	// always means to read pipes until EOF: a command whose Context has a
+	// deadline may now fail with ErrWaitDelay, or its output may be truncated,
+	// if its Stdout or Stderr writer is slow or an orphaned subprocess keeps a
+	// pipe open. Set WaitDelay to a negative value to restore the exec.Cmd
+	// behavior.
+	//
+	// If WaitDelay is negative, or is zero and Context has no deadline,
+	// I/O pipes will be read until EOF, which might not occur until orphaned
+	// subprocesses of the command have also closed their descriptors for the
+	// pipes.
 	WaitDelay time.Duration
 
+	// If KillDelay is non-zero, the command's process will be terminated with
+	// os.Kill if it is still running after KillDelay has elapsed since the
+	// Interrupt signal was sent (or Cancel was called).
+	//
+	// KillDelay allows a process to be stopped in two stages — first gently
+	// with Interrupt, then forcibly with os.Kill — without also closing its I/O
+	// pipes as WaitDelay would. If both are set, whichever expires first
+	// terminates the process.
+	KillDelay time.Duration
+
+	// If SetProcessGroup is true, the command is started in a new process group
+	// (in addition to any attributes set in SysProcAttr), and the Interrupt
+	// signal and any os.Kill sent due to KillDelay or WaitDelay are delivered to
+	// every process in that group rather than only the command's own process.
+	// This terminates any subprocesses that the command has started, provided
+	// that they have not moved to a different process group.
+	//
+	// On Windows, the process is created with CREATE_NEW_PROCESS_GROUP, but
+	// signals are delivered only to the command's own process. On platforms
+	// that do not support process groups, Start fails if SetProcessGroup is set.
+	SetProcessGroup bool
+
+	// If AllocatePTY is true, Start allocates a pseudo-terminal and connects the
+	// command's standard input, output, and error to it, so that the command
+	// observes a terminal rather than pipes. The command is started in a new
+	// session with the pseudo-terminal as its controlling terminal. After Start,
+	// the PTY method returns the controlling end of the pseudo-terminal.
+	//
+	// If AllocatePTY is set, Stdin, Stdout, and Stderr must be nil.
+	// AllocatePTY is currently supported only on Linux; on other platforms,
+	// Start fails if it is set.
+	AllocatePTY bool
+
+	// If Stderr is nil, the command's standard error is captured (regardless of
+	// how Stdout is connected), up to MaxStderr bytes (or 32 KiB if MaxStderr is
+	// zero), and if the command fails with an *exec.ExitError, Wait populates
+	// that error's Stderr field with the captured data. If MaxStderr is
+	// negative, standard error is connected to the null device instead, as it
+	// would be for an exec.Cmd.
+	//
+	// As with any other pipe, Wait waits for the captured standard error to be
+	// closed (subject to WaitDelay), which may not occur until orphaned
+	// subprocesses of the command have also exited. Set MaxStderr to a negative
+	// value for commands that may leave such subprocesses running.
+	MaxStderr int
+
+	// If StdinBytes is non-nil, Start supplies its contents as the command's
+	// standard input, as if Stdin were a bytes.Reader over StdinBytes: the
+	// command reads EOF after the last byte, and the copy is drained and closed
+	// along with the command's other I/O pipes. Each run of the command (for
+	// example, after Restart) reads StdinBytes from the beginning.
+	//
+	// If StdinBytes is non-nil, Stdin must be nil.
+	StdinBytes []byte
+
+	// Err is set by Command if the named command could not be resolved to an
+	// executable path (typically an error from exec.LookPath). If Err is
+	// non-nil, Start returns it (wrapped) without attempting to run the command.
+	Err error
+
+	// If LookPath is non-nil and the command's name (Args[0]) contains no path
+	// separators, Start calls LookPath to resolve the name, replacing Path and
+	// Err with the result, instead of using the path resolved by Command
+	// (using the package-level LookPath function).
+	LookPath func(name string) (string, error)
+
+	// If Logf is non-nil, it is called with a description of each significant
+	// event in the command's lifecycle: when the command starts (or fails to
+	// start), when Cancel is called or a signal is sent, when KillDelay or
+	// WaitDelay expires, when the I/O pipes are closed early, when the process
+	// exits, and when Wait returns.
+	//
+	// Logf is intended for diagnosing misbehaving commands, and may be set to a
+	// function such as (*testing.T).Logf or log.Printf. It may be called
+	// concurrently from goroutines other than the one that called Start or Wait,
+	// so it must be safe for concurrent use.
+	Logf func(format string, args ...interface{})
+
 	statec <-chan *os.ProcessState
-	err    error // Set before statec receives the process state.
+	done   chan struct{}    // Closed when statec receives the process state.
+	err    error            // Set before statec receives the process state.
+	pid    moreatomic.Int64 // Set once the process has started.
+	pty    *os.File         // The controlling end of the pseudo-terminal, if any.
 
-	runningPipes sync.WaitGroup
+	pipeStdio bool           // Stdin, Stdout, or Stderr was set by a Pipe method.
+	stderrBuf *boundedBuffer // Captures stderr if Stderr is nil.
+
+	runningPipes sync.WaitGroup  // copiers from the output pipes
+	inputPipes   *sync.WaitGroup // copiers to the input pipe, allocated anew for each run
 	pipeCopiers  []func()
 	localPipes   []io.Closer
 	remotePipes  []io.Closer
@@ -85,13 +224,22 @@ func Command(name string, args ...string) *Cmd {
 		Args: append([]string{name}, args...),
 	}
 	if filepath.Base(name) == name {
-		if path, err := exec.LookPath(name); err == nil {
-			c.Path = path
-		}
+		c.lookPath(LookPath, name)
 	}
 	return c
 }
 
+// lookPath sets c.Path and c.Err to the result of resolving name using
+// lookPath.
+func (c *Cmd) lookPath(lookPath func(string) (string, error), name string) {
+	c.Path = name
+	path, err := lookPath(name)
+	if path != "" {
+		c.Path = path
+	}
+	c.Err = err
+}
+
 func CommandContext(ctx context.Context, name string, args ...string) *Cmd {
 	c := Command(name, args...)
 	c.Context = ctx
@@ -99,11 +247,79 @@ func CommandContext(ctx context.Context, name string, args ...string) *Cmd {
 	return c
 }
 
+// CommandErr is like Command, but if name cannot be resolved to an executable
+// path it returns a nil Cmd and the resulting error (typically an *exec.Error
+// from exec.LookPath) instead of recording that error in the Cmd's Err field.
+//
+// Use CommandErr when the command should be validated as soon as it is
+// constructed, for example to report a misspelled program name before doing
+// any other setup. Use Command when the error can wait until Start, or when
+// the Cmd should be constructed regardless (for example, to print it).
+func CommandErr(name string, args ...string) (*Cmd, error) {
+	c := Command(name, args...)
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	return c, nil
+}
+
+// Clone returns a new, unstarted Cmd with the same configuration as c.
+//
+// The returned Cmd shares c's Stdin, Stdout, Stderr, ExtraFiles, Context, and
+// Cancel function, but not its Process, ProcessState, or internal bookkeeping.
+// Pipes returned by c's StdinPipe, StdoutPipe, or StderrPipe methods are not
+// reusable: if any were used, the caller should clear the corresponding
+// fields of the clone and call its own Pipe methods instead.
+func (c *Cmd) Clone() *Cmd {
+	attr := c.SysProcAttr
+	if attr != nil {
+		attr = new(syscall.SysProcAttr)
+		*attr = *c.SysProcAttr
+	}
+	env := c.Env
+	if env != nil {
+		// Preserve the distinction between a nil Env (which inherits the
+		// environment of the current process) and an empty one.
+		env = append(make([]string, 0, len(c.Env)), c.Env...)
+	}
+	return &Cmd{
+		Path:            c.Path,
+		Args:            append([]string(nil), c.Args...),
+		Env:             env,
+		Dir:             c.Dir,
+		Stdin:           c.Stdin,
+		Stdout:          c.Stdout,
+		Stderr:          c.Stderr,
+		MaxStderr:       c.MaxStderr,
+		StdinBytes:      c.StdinBytes,
+		ExtraFiles:      append([]*os.File(nil), c.ExtraFiles...),
+		SysProcAttr:     attr,
+		Context:         c.Context,
+		Interrupt:       c.Interrupt,
+		Cancel:          c.Cancel,
+		WaitDelay:       c.WaitDelay,
+		KillDelay:       c.KillDelay,
+		SetProcessGroup: c.SetProcessGroup,
+		AllocatePTY:     c.AllocatePTY,
+		Err:             c.Err,
+		LookPath:        c.LookPath,
+		Logf:            c.Logf,
+	}
+}
+
 func (c *Cmd) String() string {
 	return (&exec.Cmd{Path: c.Path, Args: c.Args}).String()
 }
 
 func (c *Cmd) Start() (err error) {
+	if c.LookPath != nil && c.statec == nil && len(c.Args) > 0 {
+		if name := c.Args[0]; filepath.Base(name) == name {
+			c.lookPath(c.LookPath, name)
+		}
+	}
+	if c.Err != nil {
+		return fmt.Errorf("moreexec: %w", c.Err)
+	}
 	if c.Interrupt != nil {
 		if c.Context == nil {
 			return errors.New("moreexec: Interrupt requires a non-nil Context")
@@ -112,11 +328,25 @@ func (c *Cmd) Start() (err error) {
 			return fmt.Errorf("moreexec: signal %q: %w", c.Interrupt, errWindows)
 		}
 	}
+	if c.Cancel != nil && c.Context == nil {
+		return errors.New("moreexec: Cancel requires a non-nil Context")
+	}
 
 	if c.statec != nil {
 		return errors.New("moreexec: already started")
 	}
+	if c.StdinBytes != nil && c.Stdin != nil {
+		return errors.New("moreexec: Stdin and StdinBytes are both set")
+	}
+	if c.AllocatePTY && (c.Stdin != nil || c.StdinBytes != nil || c.Stdout != nil || c.Stderr != nil) {
+		return errors.New("moreexec: AllocatePTY requires nil Stdin, Stdout, and Stderr")
+	}
+	// A copier to the input pipe may outlive the run that started it (see wait),
+	// so each run needs its own WaitGroup to keep a late Done from affecting a
+	// subsequent run started by Restart.
+	c.inputPipes = new(sync.WaitGroup)
 	statec := make(chan *os.ProcessState, 1)
+	done := make(chan struct{})
 
 	defer func() {
 		// The remote ends of the pipes are either connected to the process or
@@ -128,6 +358,7 @@ func (c *Cmd) Start() (err error) {
 
 		if err == nil {
 			c.statec = statec
+			c.done = done
 		} else {
 			// Since the process didn't start, we can also close and collect
 			// the local ends of the pipes: nothing will be writing to them.
@@ -135,38 +366,67 @@ func (c *Cmd) Start() (err error) {
 				f.Close()
 			}
 			c.localPipes = nil
+			if c.pty != nil {
+				c.pty.Close()
+				c.pty = nil
+			}
 			c.runningPipes.Wait()
+			c.inputPipes.Wait()
 		}
 	}()
 
 	cmd := exec.Command(c.Path)
 	cmd.Args = c.Args
-	if c.Dir != "" {
-		cmd.Dir = c.Dir
-		if c.Env == nil {
-			cmd.Env = append(os.Environ(), "PWD="+c.Dir)
-		} else {
-			cmd.Env = append(c.Env[:len(c.Env):len(c.Env)], "PWD="+c.Dir)
-		}
-	} else {
-		cmd.Env = c.Env
-	}
+	cmd.Dir = c.Dir
+	cmd.Env = c.Environ()
 	cmd.ExtraFiles = c.ExtraFiles
 	cmd.SysProcAttr = c.SysProcAttr
+	if c.SetProcessGroup || c.AllocatePTY {
+		attr := new(syscall.SysProcAttr)
+		if c.SysProcAttr != nil {
+			*attr = *c.SysProcAttr
+		}
+		if c.SetProcessGroup {
+			if err := setProcessGroup(attr); err != nil {
+				return err
+			}
+		}
+		if c.AllocatePTY {
+			if err := setControllingTTY(attr); err != nil {
+				return err
+			}
+		}
+		cmd.SysProcAttr = attr
+	}
 
 	// As a workaround for https://go.dev/issue/23019, we inject our own I/O pipes
 	// as needed. If we need to forcibly terminate the process, we can also close
 	// those pipes to cause the copying goroutines to exit.
 
-	if _, ok := c.Stdin.(*os.File); ok || c.Stdin == nil {
-		cmd.Stdin = c.Stdin
+	stdin := c.Stdin
+	if c.StdinBytes != nil {
+		stdin = bytes.NewReader(c.StdinBytes)
+	}
+	if _, ok := stdin.(*os.File); ok || stdin == nil {
+		cmd.Stdin = stdin
 	} else {
 		r, w, err := c.newInputPipe()
 		if err != nil {
 			return err
 		}
 		cmd.Stdin = r
-		c.startPipe(w, c.Stdin, w)
+		c.startInputPipe(w, stdin, w)
+	}
+
+	stderr := c.Stderr
+	c.stderrBuf = nil
+	if stderr == nil && c.MaxStderr >= 0 && !c.AllocatePTY {
+		n := c.MaxStderr
+		if n == 0 {
+			n = maxStderr
+		}
+		c.stderrBuf = &boundedBuffer{N: n}
+		stderr = c.stderrBuf
 	}
 
 	if _, ok := c.Stdout.(*os.File); ok || c.Stdout == nil {
@@ -176,45 +436,222 @@ func (c *Cmd) Start() (err error) {
 		if err != nil {
 			return err
 		}
-		if c.Stderr == c.Stdout {
+		if stderr == c.Stdout {
 			cmd.Stderr = w
 		}
 		cmd.Stdout = w
 		c.startPipe(c.Stdout, r, r)
 	}
 
-	if c.Stderr != c.Stdout {
-		if _, ok := c.Stderr.(*os.File); ok || c.Stderr == nil {
-			cmd.Stderr = c.Stderr
+	if stderr != c.Stdout {
+		if _, ok := stderr.(*os.File); ok || stderr == nil {
+			cmd.Stderr = stderr
 		} else {
 			r, w, err := c.newOutputPipe()
 			if err != nil {
 				return err
 			}
 			cmd.Stderr = w
-			c.startPipe(c.Stderr, r, r)
+			c.startPipe(stderr, r, r)
+		}
+	}
+
+	if c.AllocatePTY {
+		pty, tty, err := openPTY()
+		if err != nil {
+			return err
 		}
+		c.pty = pty
+		c.remotePipes = append(c.remotePipes, tty)
+		cmd.Stdin = tty
+		cmd.Stdout = tty
+		cmd.Stderr = tty
 	}
 
 	err = cmd.Start()
 	c.Process = cmd.Process
-	if err == nil {
-		go c.wait(statec, cmd)
+	if err != nil {
+		c.logf("moreexec: %v: failed to start: %v", c, err)
+		return err
+	}
+	c.pid.Store(int64(cmd.Process.Pid))
+	c.logf("moreexec: %v: started (pid %d)", c, cmd.Process.Pid)
+	go c.wait(statec, done, cmd, c.waitDelay())
+	return nil
+}
+
+// logf calls c.Logf, if it is non-nil.
+func (c *Cmd) logf(format string, args ...interface{}) {
+	if c.Logf != nil {
+		c.Logf(format, args...)
+	}
+}
+
+// PID returns the process ID of the command and true if the command has been
+// started successfully, or 0 and false otherwise.
+//
+// Unlike c.Process.Pid, PID may be called concurrently with Start.
+func (c *Cmd) PID() (int, bool) {
+	pid := c.pid.Load()
+	return int(pid), pid != 0
+}
+
+// PTY returns the controlling end of the pseudo-terminal allocated for the
+// command if AllocatePTY was set and Start succeeded, or nil otherwise.
+//
+// Reading from the PTY returns the command's output, and writing to it provides
+// the command's input. The caller is responsible for closing it.
+func (c *Cmd) PTY() io.ReadWriteCloser {
+	if c.pty == nil {
+		return nil
+	}
+	return c.pty
+}
+
+// Signal sends sig to the command's process.
+//
+// If the command has not been started, Signal returns a non-nil error.
+// If the process has already exited, Signal returns os.ErrProcessDone.
+func (c *Cmd) Signal(sig os.Signal) error {
+	if c.Process == nil {
+		return errors.New("moreexec: Signal before Start")
+	}
+	select {
+	case <-c.done:
+		return errProcessDone
+	default:
+	}
+	c.logf("moreexec: pid %d: sending signal %v", c.Process.Pid, sig)
+	if err := c.Process.Signal(sig); err != nil {
+		c.logf("moreexec: pid %d: error sending signal %v: %v", c.Process.Pid, sig, err)
+		if isProcessDone(err) {
+			return errProcessDone
+		}
+		return err
+	}
+	return nil
+}
+
+// signal sends sig to p, or to p's process group if c.SetProcessGroup is set.
+func (c *Cmd) signal(p *os.Process, sig os.Signal) (err error) {
+	// Log before sending the signal: once it is delivered, the process may exit
+	// and Wait may return before this goroutine is scheduled again.
+	target := "pid"
+	if c.SetProcessGroup {
+		target = "process group"
+	}
+	c.logf("moreexec: %s %d: sending signal %v", target, p.Pid, sig)
+	if c.SetProcessGroup {
+		err = signalGroup(p, sig)
+	} else {
+		err = p.Signal(sig)
+	}
+	if err != nil {
+		c.logf("moreexec: %s %d: error sending signal %v: %v", target, p.Pid, sig, err)
 	}
 	return err
 }
 
-func (c *Cmd) wait(statec chan<- *os.ProcessState, cmd *exec.Cmd) {
+// Environ returns a copy of the environment in which the command would be run
+// as it is currently configured: c.Env, or the environment of the current
+// process if c.Env is nil, with PWD set to c.Dir if c.Dir is non-empty.
+func (c *Cmd) Environ() []string {
+	env := c.Env
+	if env == nil {
+		env = os.Environ()
+	}
+	env = append([]string(nil), env...)
+	if c.Dir != "" {
+		env = append(env, "PWD="+c.Dir)
+	}
+	return env
+}
+
+// SetEnv sets the environment variable key to value in c.Env, replacing any
+// existing entries for key rather than adding a duplicate. If c.Env is nil,
+// SetEnv first initializes it to a copy of the current process's environment,
+// so that the command inherits every other variable. Successive calls
+// accumulate.
+//
+// On Windows, environment variable names are case-insensitive, and SetEnv
+// replaces entries for key regardless of case.
+func (c *Cmd) SetEnv(key, value string) {
+	env := c.Env
+	if env == nil {
+		env = os.Environ()
+	}
+
+	kv := key + "=" + value
+	newEnv := make([]string, 0, len(env)+1)
+	replaced := false
+	for _, e := range env {
+		if !envKeyEqual(envKey(e), key) {
+			newEnv = append(newEnv, e)
+		} else if !replaced {
+			newEnv = append(newEnv, kv)
+			replaced = true
+		}
+	}
+	if !replaced {
+		newEnv = append(newEnv, kv)
+	}
+	c.Env = newEnv
+}
+
+// envKey returns the name of the variable in the environment entry kv.
+func envKey(kv string) string {
+	// On Windows, the names of some variables (such as "=C:") begin with "=".
+	start := 0
+	if strings.HasPrefix(kv, "=") {
+		start = 1
+	}
+	if i := strings.Index(kv[start:], "="); i >= 0 {
+		return kv[:start+i]
+	}
+	return kv
+}
+
+func envKeyEqual(a, b string) bool {
+	if runtime.GOOS == "windows" {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
+
+// waitDelay returns the effective WaitDelay for c, or 0 if pipes should be read
+// until EOF.
+func (c *Cmd) waitDelay() time.Duration {
+	if c.WaitDelay != 0 {
+		if c.WaitDelay < 0 {
+			return 0
+		}
+		return c.WaitDelay
+	}
+	if c.Context == nil {
+		return 0
+	}
+	if _, ok := c.Context.Deadline(); !ok {
+		return 0
+	}
+	return DeadlineWaitDelay
+}
+
+func (c *Cmd) wait(statec chan<- *os.ProcessState, done chan<- struct{}, cmd *exec.Cmd, waitDelay time.Duration) {
 	var (
 		cancel context.CancelFunc
 		errc   chan error
+		exited = make(chan struct{})
+
+		// pipesClosed is closed if the WaitDelay expires and the local ends of
+		// the pipes are closed.
+		pipesClosed = make(chan struct{})
 	)
-	if c.Interrupt != nil || c.WaitDelay != 0 {
+	if c.Interrupt != nil || c.Cancel != nil || waitDelay != 0 {
 		ctx := c.Context
 		if ctx == nil {
 			ctx = context.Background()
 		}
-		if c.WaitDelay != 0 {
+		if waitDelay != 0 {
 			ctx, cancel = context.WithCancel(ctx)
 		}
 
@@ -226,19 +663,52 @@ func (c *Cmd) wait(statec chan<- *os.ProcessState, cmd *exec.Cmd) {
 			case <-ctx.Done():
 			}
 
-			var err error
-			if c.Interrupt != nil {
-				if signalErr := c.Process.Signal(c.Interrupt); signalErr == nil {
+			var (
+				err         error
+				interrupted bool
+			)
+			if c.Cancel != nil && c.Context.Err() != nil {
+				// Only call Cancel if c.Context itself is done: if ctx is done only
+				// because the process has already exited, Cancel has nothing to do.
+				cancelErr := c.Cancel()
+				c.logf("moreexec: pid %d: Context done (%v); Cancel returned %v", cmd.Process.Pid, c.Context.Err(), cancelErr)
+				if cancelErr == nil {
+					// Cancel appears to have done its job, so any program behavior
+					// from this point may be due to ctx.
+					err = ctx.Err()
+					interrupted = true
+				} else if !isProcessDone(cancelErr) {
+					err = fmt.Errorf("moreexec: error canceling Cmd: %w", cancelErr)
+				}
+			} else if c.Cancel == nil && c.Interrupt != nil {
+				if signalErr := c.signal(cmd.Process, c.Interrupt); signalErr == nil {
 					// We appear to have successfully delivered c.Interrupt, so any
 					// program behavior from this point may be due to ctx.
 					err = ctx.Err()
+					interrupted = true
 				} else if !isProcessDone(signalErr) {
 					err = fmt.Errorf("moreexec: error sending signal to Cmd: %w", signalErr)
 				}
 			}
 
-			if c.WaitDelay != 0 {
-				timer := time.NewTimer(c.WaitDelay)
+			if interrupted && c.KillDelay != 0 {
+				go func() {
+					timer := time.NewTimer(c.KillDelay)
+					defer timer.Stop()
+					select {
+					case <-exited:
+					case <-timer.C:
+						// The process has not exited on its own. Ignore any error from
+						// Kill: the process may have exited in the meantime, and
+						// either way Wait will report how it terminated.
+						c.logf("moreexec: pid %d: KillDelay (%v) expired", cmd.Process.Pid, c.KillDelay)
+						_ = c.signal(cmd.Process, os.Kill)
+					}
+				}()
+			}
+
+			if waitDelay != 0 {
+				timer := time.NewTimer(waitDelay)
 				select {
 				case errc <- err:
 					timer.Stop()
@@ -255,7 +725,8 @@ func (c *Cmd) wait(statec chan<- *os.ProcessState, cmd *exec.Cmd) {
 				if err == nil {
 					err = ErrWaitDelay
 				}
-				_ = cmd.Process.Kill()
+				c.logf("moreexec: pid %d: WaitDelay (%v) expired", cmd.Process.Pid, waitDelay)
+				_ = c.signal(cmd.Process, os.Kill)
 
 				// Close the pipes to which the process writes, in case the process
 				// abandoned any subprocesses that are still running. Terminate the
@@ -265,6 +736,8 @@ func (c *Cmd) wait(statec chan<- *os.ProcessState, cmd *exec.Cmd) {
 				for _, p := range c.localPipes {
 					p.Close()
 				}
+				c.logf("moreexec: pid %d: closed I/O pipes", cmd.Process.Pid)
+				close(pipesClosed)
 			}
 
 			errc <- err
@@ -272,11 +745,28 @@ func (c *Cmd) wait(statec chan<- *os.ProcessState, cmd *exec.Cmd) {
 	}
 
 	c.err = cmd.Wait()
+	c.logf("moreexec: pid %d: exited: %v", cmd.Process.Pid, cmd.ProcessState)
+	close(exited)
 	if cancel != nil {
 		cancel() // Start the WaitDelay timer, if applicable.
 	}
 	c.runningPipes.Wait()
 
+	// The goroutine copying to stdin may be blocked reading from c.Stdin, which
+	// closing the pipes cannot interrupt. Once the WaitDelay has expired, stop
+	// waiting for it: it will exit on its own (when it next writes to the closed
+	// pipe) if c.Stdin ever produces more data.
+	inputPipes := c.inputPipes
+	inputDone := make(chan struct{})
+	go func() {
+		inputPipes.Wait()
+		close(inputDone)
+	}()
+	select {
+	case <-inputDone:
+	case <-pipesClosed:
+	}
+
 	if errc != nil {
 		interruptErr := <-errc
 		// If Wait returned an error, prefer that. Otherwise,
@@ -292,12 +782,19 @@ func (c *Cmd) wait(statec chan<- *os.ProcessState, cmd *exec.Cmd) {
 	}
 	c.localPipes = nil
 
+	if c.stderrBuf != nil {
+		if ee := new(*exec.ExitError); errors.As(c.err, ee) {
+			(*ee).Stderr = c.stderrBuf.Bytes()
+		}
+	}
+
 	statec <- cmd.ProcessState
 	close(statec)
+	close(done)
 }
 
 func (c *Cmd) StdinPipe() (io.WriteCloser, error) {
-	if c.Stdin != nil {
+	if c.Stdin != nil || c.StdinBytes != nil {
 		return nil, errors.New("moreexec: Stdin already set")
 	}
 	if c.Process != nil {
@@ -305,6 +802,7 @@ func (c *Cmd) StdinPipe() (io.WriteCloser, error) {
 	}
 	r, w, err := c.newInputPipe()
 	c.Stdin = r
+	c.pipeStdio = true
 	return w, err
 }
 
@@ -317,6 +815,7 @@ func (c *Cmd) StdoutPipe() (io.ReadCloser, error) {
 	}
 	r, w, err := c.newOutputPipe()
 	c.Stdout = w
+	c.pipeStdio = true
 	return r, err
 }
 
@@ -329,6 +828,7 @@ func (c *Cmd) StderrPipe() (io.ReadCloser, error) {
 	}
 	r, w, err := c.newOutputPipe()
 	c.Stderr = w
+	c.pipeStdio = true
 	return r, err
 }
 
@@ -352,6 +852,16 @@ func (c *Cmd) newOutputPipe() (io.ReadCloser, io.WriteCloser, error) {
 	return r, w, nil
 }
 
+func (c *Cmd) startInputPipe(dst io.Writer, src io.Reader, local io.Closer) {
+	wg := c.inputPipes
+	wg.Add(1)
+	go func() {
+		io.Copy(dst, src)
+		local.Close()
+		wg.Done()
+	}()
+}
+
 func (c *Cmd) startPipe(dst io.Writer, src io.Reader, local io.Closer) {
 	c.runningPipes.Add(1)
 	go func() {
@@ -361,6 +871,46 @@ func (c *Cmd) startPipe(dst io.Writer, src io.Reader, local io.Closer) {
 	}()
 }
 
+// Restart starts a new run of the command after a previous run has completed
+// (that is, after Wait has returned), reusing its configuration.
+//
+// Restart reuses Stdin, Stdout, and Stderr as they are: writers (such as
+// buffers) receive the output of the new run appended to that of the previous
+// one, and readers are not rewound (although StdinBytes, if set, is read again
+// from the beginning). Pipes created by StdinPipe, StdoutPipe, or
+// StderrPipe are closed when the previous run completes, so Restart fails if
+// any were used; for such a command, use Clone instead. If AllocatePTY is set,
+// Restart allocates a new pseudo-terminal, and the caller remains responsible
+// for closing the previous one.
+func (c *Cmd) Restart() error {
+	if c.statec == nil {
+		return errors.New("moreexec: Restart before Start")
+	}
+	if c.ProcessState == nil {
+		return errors.New("moreexec: Restart before Wait")
+	}
+	if c.pipeStdio {
+		return errors.New("moreexec: Restart with stdio created by a Pipe method")
+	}
+
+	c.Process = nil
+	c.ProcessState = nil
+	c.statec = nil
+	c.done = nil
+	c.err = nil
+	c.pid.Store(0)
+	c.pty = nil
+	return c.Start()
+}
+
+// Done returns a channel that is closed when the command has exited and its
+// I/O has completed, at which point Wait returns immediately.
+//
+// If the command has not been started, Done returns nil.
+func (c *Cmd) Done() <-chan struct{} {
+	return c.done
+}
+
 // Wait waits for the already-started command cmd.
 func (c *Cmd) Wait() error {
 	if c.statec == nil {
@@ -372,9 +922,29 @@ func (c *Cmd) Wait() error {
 		return errors.New("moreexec: Wait was already called")
 	}
 	c.ProcessState = state
+	c.logf("moreexec: %v: Wait returned %v", c, c.err)
 	return c.err
 }
 
+// WaitContext is like Wait, but returns ctx.Err() if ctx is done before the
+// command completes.
+//
+// If ctx is done first, the command is not affected: it continues to run, and
+// is still subject to c.Context, Interrupt, Cancel, and WaitDelay. The caller
+// must still call Wait (or WaitContext again) to release its resources and
+// obtain its result.
+func (c *Cmd) WaitContext(ctx context.Context) error {
+	if c.statec == nil {
+		return errors.New("moreexec: not started")
+	}
+	select {
+	case <-c.done:
+		return c.Wait()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // CombinedOutput runs the command and returns its combined standard output and
 // standard error.
 func (c *Cmd) CombinedOutput() ([]byte, error) {
@@ -392,6 +962,78 @@ func (c *Cmd) CombinedOutput() ([]byte, error) {
 	return b.Bytes(), err
 }
 
+// CombinedOutputTimeout is like CombinedOutput, but stops the command if it
+// has not completed within the duration d.
+//
+// While the command runs, CombinedOutputTimeout sets c.Context to a context
+// that expires after d (derived from c.Context, if it is non-nil), and if
+// neither c.Interrupt nor c.Cancel is set, it also sets c.Interrupt to os.Kill.
+// It restores both fields before returning, so that a subsequent Restart or
+// Clone uses the caller's configuration. If the command fails after the context
+// expires, CombinedOutputTimeout returns the output collected so far along with
+// the context's error (typically context.DeadlineExceeded).
+func (c *Cmd) CombinedOutputTimeout(d time.Duration) ([]byte, error) {
+	parent := c.Context
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(parent, d)
+	defer cancel()
+
+	savedCtx, savedInterrupt := c.Context, c.Interrupt
+	defer func() {
+		c.Context, c.Interrupt = savedCtx, savedInterrupt
+	}()
+
+	c.Context = ctx
+	if c.Interrupt == nil && c.Cancel == nil {
+		c.Interrupt = os.Kill
+	}
+	out, err := c.CombinedOutput()
+	if err != nil && ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	return out, err
+}
+
+// Output runs the command and returns its standard output.
+//
+// If c.Stderr was nil, Output captures a bounded amount of the command's
+// standard error (see MaxStderr), and if the command fails with an
+// *exec.ExitError, Output populates that error's Stderr field with the
+// captured data.
+func (c *Cmd) Output() ([]byte, error) {
+	if c.Stdout != nil {
+		return nil, errors.New("moreexec: Stdout already set")
+	}
+	stdout := new(bytes.Buffer)
+	c.Stdout = stdout
+	err := c.Run()
+	return stdout.Bytes(), err
+}
+
+// maxStderr is the default maximum number of bytes of standard error captured
+// when Stderr is nil, matching the limit used by exec.Cmd.Output.
+const maxStderr = 32 << 10
+
+// A boundedBuffer retains only the first N bytes written to it,
+// silently discarding the rest.
+type boundedBuffer struct {
+	buf bytes.Buffer
+	N   int
+}
+
+func (b *boundedBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	if room := b.N - b.buf.Len(); len(p) > room {
+		p = p[:room]
+	}
+	b.buf.Write(p)
+	return n, nil
+}
+
+func (b *boundedBuffer) Bytes() []byte { return b.buf.Bytes() }
+
 func (c *Cmd) Run() error {
 	err := c.Start()
 	if err == nil {
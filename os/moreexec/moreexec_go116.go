@@ -12,6 +12,8 @@ import (
 	"os"
 )
 
+var errProcessDone = os.ErrProcessDone
+
 func isProcessDone(err error) bool {
 	return errors.Is(err, os.ErrProcessDone)
 }
@@ -7,8 +7,20 @@
 
 package moreexec
 
-import "os"
+import (
+	"errors"
+	"os"
+	"syscall"
+)
 
 var quitSignal os.Signal = nil
 
 var errWindows error = nil
+
+func setProcessGroup(attr *syscall.SysProcAttr) error {
+	return errors.New("moreexec: SetProcessGroup is not supported on this platform")
+}
+
+func signalGroup(p *os.Process, sig os.Signal) error {
+	return p.Signal(sig)
+}
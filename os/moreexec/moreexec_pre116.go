@@ -8,9 +8,12 @@
 package moreexec
 
 import (
+	"errors"
 	"strings"
 )
 
+var errProcessDone = errors.New("os: process already finished")
+
 func isProcessDone(err error) bool {
 	return strings.HasSuffix(err.Error(), "os: process already finished")
 }
@@ -13,6 +13,7 @@ import (
 	"os"
 	"os/exec"
 	"os/signal"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"sync"
@@ -27,7 +28,13 @@ var (
 	sleep           = flag.Duration("sleep", 0, "amount of time to sleep instead of running tests")
 	exitOnInterrupt = flag.Bool("interrupt", false, "if true, exit 0 on os.Interrupt")
 	subsleep        = flag.Duration("subsleep", 0, "amount of time to leave an orphaned subprocess sleeping with stderr open")
+	substdout       = flag.Bool("substdout", false, "if true, connect the stderr of the -subsleep subprocess to stdout")
 	probe           = flag.Duration("probe", 0, "if nonzero, period at which to print to stderr to check for liveness")
+	stdout          = flag.String("stdout", "", "if nonempty, a string to print to stdout instead of running tests")
+	stderr          = flag.String("stderr", "", "if nonempty, a string to print to stderr instead of running tests")
+	exitCode        = flag.Int("exit", 0, "if nonzero, the exit code to use instead of running tests")
+	readStdin       = flag.Bool("readstdin", false, "if true, read stdin until EOF instead of running tests")
+	catStdin        = flag.Bool("cat", false, "if true, copy stdin to stdout instead of running tests")
 )
 
 var exeOnce struct {
@@ -67,6 +74,9 @@ func TestMain(m *testing.M) {
 	if *subsleep != 0 {
 		cmd := moreexec.Command(exePath(), "-sleep", subsleep.String(), "-probe", probe.String())
 		cmd.Stderr = os.Stderr
+		if *substdout {
+			cmd.Stderr = os.Stdout
+		}
 		out, err := cmd.StdoutPipe()
 		if err != nil {
 			fmt.Fprintln(os.Stderr, err)
@@ -103,6 +113,26 @@ func TestMain(m *testing.M) {
 		}
 	}
 
+	if *readStdin {
+		n, err := io.Copy(io.Discard, os.Stdin)
+		fmt.Fprintln(os.Stderr, pid, "read", n, "bytes from stdin:", err)
+		os.Exit(0)
+	}
+
+	if *catStdin {
+		if _, err := io.Copy(os.Stdout, os.Stdin); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	if *stdout != "" || *stderr != "" || *exitCode != 0 {
+		fmt.Fprint(os.Stdout, *stdout)
+		fmt.Fprint(os.Stderr, *stderr)
+		os.Exit(*exitCode)
+	}
+
 	if *probe != 0 || *subsleep != 0 || *sleep != 0 {
 		fmt.Fprintln(os.Stderr, pid, "exiting")
 		os.Exit(0)
@@ -299,3 +329,790 @@ func TestWaitContext(t *testing.T) {
 		}
 	})
 }
+
+func TestOutput(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		cmd := moreexec.Command(exePath(), "-stdout=hello", "-stderr=ignored")
+		out, err := cmd.Output()
+		if string(out) != "hello" || err != nil {
+			t.Errorf("Output() = %q, %v; want \"hello\", <nil>", out, err)
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		cmd := moreexec.Command(exePath(), "-stdout=partial", "-stderr=oops", "-exit=3")
+		out, err := cmd.Output()
+		t.Logf("Output() = %q, %v", out, err)
+		if string(out) != "partial" {
+			t.Errorf("Output() = %q; want \"partial\"", out)
+		}
+		ee := new(*exec.ExitError)
+		if !errors.As(err, ee) {
+			t.Fatalf("Output error = %v; want %T", err, *ee)
+		}
+		if string((*ee).Stderr) != "oops" {
+			t.Errorf("ExitError.Stderr = %q; want \"oops\"", (*ee).Stderr)
+		}
+		if code := (*ee).ExitCode(); code != 3 {
+			t.Errorf("ExitCode() = %v; want 3", code)
+		}
+	})
+
+	t.Run("Stdout already set", func(t *testing.T) {
+		cmd := moreexec.Command(exePath(), "-stdout=hello")
+		cmd.Stdout = new(strings.Builder)
+		if _, err := cmd.Output(); err == nil {
+			t.Errorf("Output succeeded unexpectedly with Stdout set")
+		}
+	})
+}
+
+func TestCancel(t *testing.T) {
+	t.Run("Kill", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cmd := moreexec.CommandContext(ctx, exePath(), "-sleep=10m")
+		cmd.Interrupt = nil
+		canceled := false
+		cmd.Cancel = func() error {
+			canceled = true
+			return cmd.Process.Kill()
+		}
+		if err := cmd.Start(); err != nil {
+			t.Fatal(err)
+		}
+		cancel()
+		err := cmd.Wait()
+		t.Logf("[%d] %v", cmd.Process.Pid, err)
+
+		if !canceled {
+			t.Errorf("Cancel was not called")
+		}
+		if ee := new(*exec.ExitError); !errors.As(err, ee) {
+			t.Errorf("Wait error = %v; want %T", err, *ee)
+		}
+	})
+
+	t.Run("not called after exit", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		cmd := moreexec.CommandContext(ctx, exePath(), "-sleep=1ms")
+		cmd.WaitDelay = 10 * time.Second
+		cmd.Cancel = func() error {
+			t.Errorf("Cancel called unexpectedly")
+			return nil
+		}
+		if err := cmd.Run(); err != nil {
+			t.Errorf("Run: %v; want <nil>", err)
+		}
+	})
+
+	t.Run("requires Context", func(t *testing.T) {
+		cmd := moreexec.Command(exePath(), "-sleep=1ms")
+		cmd.Cancel = func() error { return nil }
+		if err := cmd.Start(); err == nil {
+			t.Errorf("Start succeeded unexpectedly")
+			cmd.Wait()
+		}
+	})
+}
+
+func TestEnviron(t *testing.T) {
+	cmd := moreexec.Command(exePath())
+	if got, want := len(cmd.Environ()), len(os.Environ()); got != want {
+		t.Errorf("with nil Env: len(Environ()) = %v; want %v", got, want)
+	}
+
+	cmd.Env = []string{"A=1"}
+	cmd.Dir = os.TempDir()
+	env := cmd.Environ()
+	if want := []string{"A=1", "PWD=" + cmd.Dir}; fmt.Sprint(env) != fmt.Sprint(want) {
+		t.Errorf("Environ() = %q; want %q", env, want)
+	}
+
+	env[0] = "B=2"
+	if cmd.Env[0] != "A=1" {
+		t.Errorf("modifying the result of Environ changed cmd.Env")
+	}
+}
+
+func TestSetEnv(t *testing.T) {
+	cmd := moreexec.Command(exePath())
+	cmd.SetEnv("MOREEXEC_TEST_A", "1")
+	cmd.SetEnv("MOREEXEC_TEST_B", "2")
+	cmd.SetEnv("MOREEXEC_TEST_A", "3")
+
+	// The inherited environment is retained.
+	if got, want := len(cmd.Env), len(os.Environ())+2; got != want {
+		t.Errorf("after SetEnv: len(Env) = %v; want %v", got, want)
+	}
+
+	var a, b []string
+	for _, kv := range cmd.Env {
+		switch {
+		case strings.HasPrefix(kv, "MOREEXEC_TEST_A="):
+			a = append(a, kv)
+		case strings.HasPrefix(kv, "MOREEXEC_TEST_B="):
+			b = append(b, kv)
+		}
+	}
+	if fmt.Sprint(a) != "[MOREEXEC_TEST_A=3]" || fmt.Sprint(b) != "[MOREEXEC_TEST_B=2]" {
+		t.Errorf("Env entries = %q, %q; want [MOREEXEC_TEST_A=3], [MOREEXEC_TEST_B=2]", a, b)
+	}
+
+	// Duplicate entries in an explicit Env are collapsed into one.
+	cmd.Env = []string{"A=1", "B=2", "A=3"}
+	env := cmd.Env
+	cmd.SetEnv("A", "4")
+	if want := []string{"A=4", "B=2"}; fmt.Sprint(cmd.Env) != fmt.Sprint(want) {
+		t.Errorf("after SetEnv(\"A\", \"4\"): Env = %q; want %q", cmd.Env, want)
+	}
+	if env[0] != "A=1" {
+		t.Errorf("SetEnv modified the previous Env slice in place")
+	}
+}
+
+func TestKillDelay(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skipf("skipping: os.Interrupt is not implemented on Windows")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd := moreexec.CommandContext(ctx, exePath(), "-sleep=10m", "-interrupt=false")
+	cmd.Interrupt = os.Interrupt
+	cmd.KillDelay = 10 * time.Millisecond
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	// Wait for cmd to close stdout to signal that its handlers are installed.
+	io.Copy(io.Discard, out)
+
+	cancel()
+	err = cmd.Wait()
+	t.Logf("[%d] %v", cmd.Process.Pid, err)
+
+	// This command ignores SIGINT, sleeping until it is killed.
+	if ee := new(*exec.ExitError); !errors.As(err, ee) {
+		t.Errorf("Wait error = %v; want %T", err, *ee)
+	}
+	if ps := cmd.ProcessState; ps.Exited() {
+		t.Errorf("cmd unexpectedly exited: %v", ps)
+	} else if sys, ok := ps.Sys().(interface{ Signal() syscall.Signal }); ok && sys.Signal() != os.Kill {
+		t.Errorf("cmd.ProcessState.Sys().Signal() = %v; want %v", sys.Signal(), os.Kill)
+	}
+}
+
+func TestPID(t *testing.T) {
+	cmd := moreexec.Command(exePath(), "-sleep=1ms")
+	if pid, ok := cmd.PID(); ok {
+		t.Errorf("before Start: PID() = %v, true; want 0, false", pid)
+	}
+
+	started := make(chan struct{})
+	go func() {
+		defer close(started)
+		cmd.Start()
+	}()
+	for {
+		// PID should be safe to call concurrently with Start.
+		if _, ok := cmd.PID(); ok {
+			break
+		}
+		time.Sleep(100 * time.Microsecond)
+	}
+	<-started
+
+	pid, ok := cmd.PID()
+	if !ok || pid != cmd.Process.Pid {
+		t.Errorf("after Start: PID() = %v, %v; want %v, true", pid, ok, cmd.Process.Pid)
+	}
+	if err := cmd.Wait(); err != nil {
+		t.Errorf("Wait: %v", err)
+	}
+
+	cmd = moreexec.Command(filepath.Join(t.TempDir(), "does-not-exist"))
+	if err := cmd.Start(); err == nil {
+		t.Fatalf("Start succeeded unexpectedly")
+	}
+	if pid, ok := cmd.PID(); ok {
+		t.Errorf("after failed Start: PID() = %v, true; want 0, false", pid)
+	}
+}
+
+func TestWaitDelayStdin(t *testing.T) {
+	t.Run("StdinPipe", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cmd := moreexec.CommandContext(ctx, exePath(), "-readstdin")
+		cmd.Interrupt = nil
+		cmd.WaitDelay = 10 * time.Millisecond
+		stdin, err := cmd.StdinPipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer stdin.Close()
+		if err := cmd.Start(); err != nil {
+			t.Fatal(err)
+		}
+
+		// We never close stdin, so the command would run forever,
+		// but WaitDelay should cause it to be killed.
+		cancel()
+		err = cmd.Wait()
+		t.Logf("[%d] %v", cmd.Process.Pid, err)
+		if ee := new(*exec.ExitError); !errors.As(err, ee) {
+			t.Errorf("Wait error = %v; want %T", err, *ee)
+		}
+	})
+
+	t.Run("blocked Stdin", func(t *testing.T) {
+		// pr never returns from Read, so the goroutine copying from it to the
+		// command's stdin never completes.
+		pr, pw := io.Pipe()
+		defer pw.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cmd := moreexec.CommandContext(ctx, exePath(), "-readstdin")
+		cmd.Stdin = pr
+		cmd.WaitDelay = 10 * time.Millisecond
+		if err := cmd.Start(); err != nil {
+			t.Fatal(err)
+		}
+
+		cancel()
+		err := cmd.Wait()
+		t.Logf("[%d] %v", cmd.Process.Pid, err)
+		if ee := new(*exec.ExitError); !errors.As(err, ee) {
+			t.Errorf("Wait error = %v; want %T", err, *ee)
+		}
+	})
+}
+
+// A blockOnceReader blocks in its first call to Read until release is closed,
+// and otherwise returns io.EOF.
+type blockOnceReader struct {
+	mu      sync.Mutex
+	called  bool
+	release chan struct{}
+}
+
+func (r *blockOnceReader) Read(p []byte) (int, error) {
+	r.mu.Lock()
+	first := !r.called
+	r.called = true
+	r.mu.Unlock()
+	if first {
+		<-r.release
+	}
+	return 0, io.EOF
+}
+
+func TestRestartBlockedStdin(t *testing.T) {
+	// The first run's goroutine copying from stdin to the command remains
+	// blocked after that run completes (by way of WaitDelay). It must not
+	// prevent later runs, whose stdin copies complete immediately, from
+	// completing normally.
+	stdin := &blockOnceReader{release: make(chan struct{})}
+	defer close(stdin.release)
+
+	cmd := moreexec.Command(exePath(), "-stdout=hello")
+	cmd.Stdin = stdin
+	cmd.WaitDelay = 500 * time.Millisecond
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	err := cmd.Wait()
+	t.Logf("[%d] %v", cmd.Process.Pid, err)
+	if !errors.Is(err, moreexec.ErrWaitDelay) {
+		t.Errorf("Wait error = %v; want %v", err, moreexec.ErrWaitDelay)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := cmd.Restart(); err != nil {
+			t.Fatal(err)
+		}
+		err := cmd.Wait()
+		t.Logf("[%d] %v", cmd.Process.Pid, err)
+		if err != nil {
+			t.Errorf("Wait error after Restart = %v; want <nil>", err)
+		}
+	}
+}
+
+func TestDone(t *testing.T) {
+	cmd := moreexec.Command(exePath(), "-stdout=hello", "-exit=3")
+	if done := cmd.Done(); done != nil {
+		t.Errorf("before Start: Done() = %v; want nil", done)
+	}
+	stdout := new(strings.Builder)
+	cmd.Stdout = stdout
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-cmd.Done():
+	case <-time.After(1 * time.Minute):
+		t.Fatalf("Done not closed after 1m")
+	}
+
+	// After Done is closed, all output should have been copied and Wait
+	// should report the result without blocking.
+	if got := stdout.String(); got != "hello" {
+		t.Errorf("stdout = %q; want %q", got, "hello")
+	}
+	err := cmd.Wait()
+	if ee := new(*exec.ExitError); !errors.As(err, ee) || (*ee).ExitCode() != 3 {
+		t.Errorf("Wait error = %v; want exit status 3", err)
+	}
+}
+
+func TestCommandNotFound(t *testing.T) {
+	const name = "moreexec-test-command-does-not-exist"
+	cmd := moreexec.Command(name)
+	if !errors.Is(cmd.Err, exec.ErrNotFound) {
+		t.Errorf("Command(%q).Err = %v; want %v", name, cmd.Err, exec.ErrNotFound)
+	}
+
+	err := cmd.Start()
+	t.Logf("Start: %v", err)
+	if !errors.Is(err, exec.ErrNotFound) {
+		t.Errorf("Start error = %v; want %v", err, exec.ErrNotFound)
+	}
+	if cmd.Process != nil {
+		t.Errorf("Start set cmd.Process unexpectedly")
+	}
+}
+
+func TestCommandErr(t *testing.T) {
+	const name = "moreexec-test-command-does-not-exist"
+	cmd, err := moreexec.CommandErr(name)
+	if cmd != nil || !errors.Is(err, exec.ErrNotFound) {
+		t.Errorf("CommandErr(%q) = %v, %v; want <nil>, %v", name, cmd, err, exec.ErrNotFound)
+	}
+
+	cmd, err = moreexec.CommandErr(exePath(), "-stdout=hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := cmd.Output()
+	if err != nil || string(out) != "hello" {
+		t.Errorf("Output() = %q, %v; want %q, <nil>", out, err, "hello")
+	}
+}
+
+func TestLookPath(t *testing.T) {
+	const name = "moreexec-test-tool"
+	fakeLookPath := func(file string) (string, error) {
+		if file != name {
+			return "", &exec.Error{Name: file, Err: exec.ErrNotFound}
+		}
+		return exePath(), nil
+	}
+
+	defer func(lookPath func(string) (string, error)) {
+		moreexec.LookPath = lookPath
+	}(moreexec.LookPath)
+	moreexec.LookPath = fakeLookPath
+
+	cmd := moreexec.Command(name, "-stdout=hello")
+	if cmd.Err != nil || cmd.Path != exePath() {
+		t.Fatalf("Command(%q) has Path %q, Err %v; want %q, <nil>", name, cmd.Path, cmd.Err, exePath())
+	}
+	out, err := cmd.Output()
+	if err != nil || string(out) != "hello" {
+		t.Errorf("Output() = %q, %v; want %q, <nil>", out, err, "hello")
+	}
+
+	// A per-Cmd LookPath overrides the result from the package-level one.
+	cmd = moreexec.Command(name, "-stdout=hello")
+	cmd.LookPath = func(file string) (string, error) {
+		return "", &exec.Error{Name: file, Err: exec.ErrNotFound}
+	}
+	if err := cmd.Start(); !errors.Is(err, exec.ErrNotFound) {
+		t.Errorf("Start with failing LookPath: err = %v; want %v", err, exec.ErrNotFound)
+		if err == nil {
+			cmd.Wait()
+		}
+	}
+
+	moreexec.LookPath = exec.LookPath
+	cmd = moreexec.Command(name, "-stdout=hello")
+	if !errors.Is(cmd.Err, exec.ErrNotFound) {
+		t.Fatalf("Command(%q).Err = %v; want %v", name, cmd.Err, exec.ErrNotFound)
+	}
+	cmd.LookPath = fakeLookPath
+	out, err = cmd.Output()
+	if err != nil || string(out) != "hello" {
+		t.Errorf("with per-Cmd LookPath: Output() = %q, %v; want %q, <nil>", out, err, "hello")
+	}
+}
+
+func TestClone(t *testing.T) {
+	cmd := moreexec.Command(exePath(), "-stdout=hello")
+	cmd.Env = []string{}
+	stdout := new(strings.Builder)
+	cmd.Stdout = stdout
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err == nil {
+		t.Fatalf("second Start succeeded unexpectedly")
+	}
+
+	c2 := cmd.Clone()
+	if c2.Process != nil || c2.ProcessState != nil {
+		t.Errorf("Clone copied process state")
+	}
+	if c2.Env == nil || len(c2.Env) != 0 {
+		t.Errorf("Clone().Env = %#v; want empty, non-nil", c2.Env)
+	}
+	c2.Args[0] = "modified"
+	if cmd.Args[0] == "modified" {
+		t.Errorf("Clone shares Args with the original Cmd")
+	}
+	c2.Args[0] = cmd.Args[0]
+
+	if err := c2.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := stdout.String(), "hellohello"; got != want {
+		t.Errorf("stdout = %q; want %q", got, want)
+	}
+}
+
+func TestDeadlineWaitDelay(t *testing.T) {
+	t.Run("Exit-hang", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Millisecond)
+		defer cancel()
+
+		// With no explicit WaitDelay, the deadline on ctx should bound the time
+		// spent waiting for the orphaned grandchild to close its stderr.
+		cmd := moreexec.CommandContext(ctx, exePath(), "-subsleep=10m", "-probe=1ms")
+		cmd.Interrupt = nil
+		cmd.Stderr = new(strings.Builder)
+		if err := cmd.Start(); err != nil {
+			t.Fatal(err)
+		}
+		err := cmd.Wait()
+		t.Logf("[%d] %v", cmd.Process.Pid, err)
+
+		// Depending on how quickly the grandchild starts, the child may have
+		// exited on its own (leaving the pipe open) or may still be running when
+		// DeadlineWaitDelay expires (and be killed).
+		if ee := new(*exec.ExitError); !errors.Is(err, moreexec.ErrWaitDelay) && !errors.As(err, ee) {
+			t.Errorf("Wait error = %v; want %v or %T", err, moreexec.ErrWaitDelay, *ee)
+		}
+	})
+
+	t.Run("stdout-held", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+		defer cancel()
+
+		// The orphaned grandchild holds stdout open. The wait for it should be
+		// bounded by DeadlineWaitDelay, not by the time remaining until the
+		// deadline.
+		cmd := moreexec.CommandContext(ctx, exePath(), "-subsleep=10m", "-substdout", "-probe=1ms")
+		cmd.MaxStderr = -1
+		t0 := time.Now()
+		_, err := cmd.Output()
+		d := time.Since(t0)
+		t.Logf("[%d] %v after %v", cmd.Process.Pid, err, d)
+
+		if !errors.Is(err, moreexec.ErrWaitDelay) {
+			t.Errorf("Output error = %v; want %v", err, moreexec.ErrWaitDelay)
+		}
+		if d > 10*moreexec.DeadlineWaitDelay {
+			t.Errorf("Output took %v; want approximately %v", d, moreexec.DeadlineWaitDelay)
+		}
+	})
+
+	t.Run("negative-stdout-held", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+		defer cancel()
+
+		// With a negative WaitDelay, stdout is read until EOF, which occurs only
+		// when the grandchild exits.
+		const subsleep = 2 * moreexec.DeadlineWaitDelay
+		cmd := moreexec.CommandContext(ctx, exePath(), "-subsleep="+subsleep.String(), "-substdout")
+		cmd.MaxStderr = -1
+		cmd.WaitDelay = -1
+		t0 := time.Now()
+		_, err := cmd.Output()
+		d := time.Since(t0)
+		t.Logf("[%d] %v after %v", cmd.Process.Pid, err, d)
+
+		if err != nil {
+			t.Errorf("Output error = %v; want <nil>", err)
+		}
+		if d < subsleep {
+			t.Errorf("Output took %v; want at least %v", d, subsleep)
+		}
+	})
+
+	t.Run("negative", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+		defer cancel()
+
+		cmd := moreexec.CommandContext(ctx, exePath(), "-stdout=hello")
+		cmd.WaitDelay = -1
+		out, err := cmd.Output()
+		if err != nil || string(out) != "hello" {
+			t.Errorf("Output() = %q, %v; want %q, <nil>", out, err, "hello")
+		}
+	})
+}
+
+func TestRestart(t *testing.T) {
+	cmd := moreexec.Command(exePath(), "-stdout=hello")
+	stdout := new(strings.Builder)
+	cmd.Stdout = stdout
+	if err := cmd.Restart(); err == nil {
+		t.Fatalf("Restart before Start succeeded unexpectedly")
+	}
+
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Restart(); err == nil {
+		t.Fatalf("Restart before Wait succeeded unexpectedly")
+	}
+	if err := cmd.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	firstPID := cmd.Process.Pid
+
+	if err := cmd.Restart(); err != nil {
+		t.Fatal(err)
+	}
+	if cmd.Process.Pid == firstPID {
+		t.Errorf("Restart did not start a new process")
+	}
+	if err := cmd.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := stdout.String(), "hellohello"; got != want {
+		t.Errorf("stdout = %q; want %q", got, want)
+	}
+
+	piped := moreexec.Command(exePath(), "-stdout=hello")
+	out, err := piped.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := piped.Start(); err != nil {
+		t.Fatal(err)
+	}
+	io.Copy(io.Discard, out)
+	if err := piped.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if err := piped.Restart(); err == nil {
+		t.Errorf("Restart with StdoutPipe succeeded unexpectedly")
+		piped.Wait()
+	} else {
+		t.Logf("Restart: %v", err)
+	}
+}
+
+func TestCombinedOutputTimeout(t *testing.T) {
+	cmd := moreexec.Command(exePath(), "-stdout=hello", "-stderr=, world")
+	out, err := cmd.CombinedOutputTimeout(1 * time.Minute)
+	if err != nil || string(out) != "hello, world" {
+		t.Errorf("CombinedOutputTimeout(1m) = %q, %v; want %q, <nil>", out, err, "hello, world")
+	}
+
+	cmd = moreexec.Command(exePath(), "-sleep=10m", "-probe=1ms")
+	out, err = cmd.CombinedOutputTimeout(100 * time.Millisecond)
+	t.Logf("output:\n%s", out)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("CombinedOutputTimeout(100ms) error = %v; want %v", err, context.DeadlineExceeded)
+	}
+	if cmd.Context != nil || cmd.Interrupt != nil {
+		t.Errorf("after CombinedOutputTimeout: Context = %v, Interrupt = %v; want <nil>, <nil>", cmd.Context, cmd.Interrupt)
+	}
+
+	// Restart should not reuse the expired timeout.
+	cmd = moreexec.Command(exePath(), "-stdout=hello", "-stderr=, world")
+	var b strings.Builder
+	if _, err := cmd.CombinedOutputTimeout(1 * time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	cmd.Stdout = &b
+	cmd.Stderr = &b
+	if err := cmd.Restart(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Wait(); err != nil || b.String() != "hello, world" {
+		t.Errorf("Restart after CombinedOutputTimeout: output %q, Wait error %v; want %q, <nil>", b.String(), err, "hello, world")
+	}
+}
+
+func TestStderrCapture(t *testing.T) {
+	for _, mode := range []struct {
+		name string
+		run  func(*moreexec.Cmd) error
+	}{
+		{"StdoutPipe", func(cmd *moreexec.Cmd) error {
+			stdout, err := cmd.StdoutPipe()
+			if err != nil {
+				return err
+			}
+			if err := cmd.Start(); err != nil {
+				return err
+			}
+			io.Copy(io.Discard, stdout)
+			return cmd.Wait()
+		}},
+		{"Run", func(cmd *moreexec.Cmd) error { return cmd.Run() }},
+		{"Output", func(cmd *moreexec.Cmd) error { _, err := cmd.Output(); return err }},
+	} {
+		for _, tc := range []struct {
+			maxStderr int
+			want      string
+		}{
+			{0, "oops"},
+			{2, "oo"},
+			{-1, ""},
+		} {
+			cmd := moreexec.Command(exePath(), "-stdout=partial", "-stderr=oops", "-exit=3")
+			cmd.MaxStderr = tc.maxStderr
+			err := mode.run(cmd)
+
+			ee := new(*exec.ExitError)
+			if !errors.As(err, ee) {
+				t.Fatalf("%s with MaxStderr=%d: error = %v; want %T", mode.name, tc.maxStderr, err, *ee)
+			}
+			if got := string((*ee).Stderr); got != tc.want {
+				t.Errorf("%s with MaxStderr=%d: ExitError.Stderr = %q; want %q", mode.name, tc.maxStderr, got, tc.want)
+			}
+		}
+	}
+}
+
+func TestWaitContextMethod(t *testing.T) {
+	cmd := moreexec.Command(exePath(), "-sleep=10m")
+	if err := cmd.WaitContext(context.Background()); err == nil {
+		t.Errorf("WaitContext before Start succeeded unexpectedly")
+	}
+
+	cmdCtx, cmdCancel := context.WithCancel(context.Background())
+	defer cmdCancel()
+	cmd = start(t, cmdCtx, os.Kill, 0, "-sleep=10m")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := cmd.WaitContext(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("WaitContext = %v; want %v", err, context.DeadlineExceeded)
+	}
+	select {
+	case <-cmd.Done():
+		t.Fatalf("command completed after WaitContext returned early")
+	default:
+	}
+
+	// The command is still running and subject to its own Context.
+	cmdCancel()
+	err := cmd.WaitContext(context.Background())
+	if ee := new(*exec.ExitError); !errors.As(err, ee) {
+		t.Errorf("WaitContext after cancel = %v; want %T", err, *ee)
+	}
+}
+
+func TestStdinBytes(t *testing.T) {
+	cmd := moreexec.Command(exePath(), "-readstdin")
+	cmd.StdinBytes = []byte("hello\n")
+	stderr := new(strings.Builder)
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	// StdinBytes should be read from the beginning on each run.
+	if err := cmd.Restart(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Wait(); err != nil {
+		t.Fatal(err)
+	}
+
+	const want = "read 6 bytes from stdin: <nil>"
+	if got := strings.Count(stderr.String(), want); got != 2 {
+		t.Errorf("stderr:\n%s\nwant 2 lines containing %q", stderr, want)
+	}
+
+	both := moreexec.Command(exePath(), "-readstdin")
+	both.StdinBytes = []byte("hello\n")
+	both.Stdin = strings.NewReader("hello\n")
+	if err := both.Start(); err == nil {
+		t.Errorf("Start with both Stdin and StdinBytes succeeded unexpectedly")
+		both.Wait()
+	} else {
+		t.Logf("Start: %v", err)
+	}
+
+	both.Stdin = nil
+	if _, err := both.StdinPipe(); err == nil {
+		t.Errorf("StdinPipe with StdinBytes set succeeded unexpectedly")
+	}
+}
+
+func TestLogf(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skipf("skipping: os.Interrupt is not implemented on Windows")
+	}
+
+	var (
+		mu   sync.Mutex
+		logs []string
+	)
+	logf := func(format string, args ...interface{}) {
+		msg := fmt.Sprintf(format, args...)
+		t.Log(msg)
+		mu.Lock()
+		logs = append(logs, msg)
+		mu.Unlock()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd := moreexec.CommandContext(ctx, exePath(), "-sleep=10m", "-interrupt=false")
+	cmd.Interrupt = os.Interrupt
+	cmd.KillDelay = 10 * time.Millisecond
+	cmd.Logf = logf
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	// Wait for cmd to close stdout to signal that its handlers are installed.
+	io.Copy(io.Discard, out)
+
+	cancel()
+	cmd.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, want := range []string{
+		"started",
+		"sending signal " + os.Interrupt.String(),
+		"KillDelay",
+		"sending signal " + os.Kill.String(),
+		"exited",
+		"Wait returned",
+	} {
+		found := false
+		for _, msg := range logs {
+			if strings.Contains(msg, want) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("no log message containing %q", want)
+		}
+	}
+}
@@ -8,6 +8,7 @@
 package moreexec
 
 import (
+	"fmt"
 	"os"
 	"syscall"
 )
@@ -15,3 +16,24 @@ import (
 var quitSignal os.Signal = syscall.SIGQUIT
 
 var errWindows error = nil
+
+func setProcessGroup(attr *syscall.SysProcAttr) error {
+	attr.Setpgid = true
+	attr.Pgid = 0
+	return nil
+}
+
+// signalGroup sends sig to every process in the process group led by p.
+func signalGroup(p *os.Process, sig os.Signal) error {
+	s, ok := sig.(syscall.Signal)
+	if !ok {
+		return fmt.Errorf("moreexec: unsupported signal type %T", sig)
+	}
+	if err := syscall.Kill(-p.Pid, s); err != nil {
+		if err == syscall.ESRCH {
+			return errProcessDone
+		}
+		return os.NewSyscallError("kill", err)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris || zos
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package moreexec_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bcmills/more/os/moreexec"
+)
+
+func TestSetProcessGroup(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const waitDelay = 1 * time.Minute
+	cmd := moreexec.CommandContext(ctx, exePath(), "-sleep=10m", "-subsleep=10m", "-probe=1ms")
+	cmd.Stderr = new(strings.Builder)
+	cmd.Interrupt = os.Kill
+	cmd.WaitDelay = waitDelay
+	cmd.SetProcessGroup = true
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Wait for cmd to close stdout, which it does only after the grandchild
+	// process has started.
+	if _, err := io.Copy(io.Discard, out); err != nil {
+		t.Error(err)
+	}
+
+	start := time.Now()
+	cancel()
+	err = cmd.Wait()
+	elapsed := time.Since(start)
+	t.Logf("stderr:\n%s", cmd.Stderr)
+	t.Logf("[%d] %v", cmd.Process.Pid, err)
+
+	if ee := new(*exec.ExitError); !errors.As(err, ee) {
+		t.Errorf("Wait error = %v; want %T", err, *ee)
+	}
+
+	// The grandchild process holds the stderr pipe open, so Wait can return
+	// before WaitDelay only if the grandchild was killed along with the child.
+	if elapsed >= waitDelay {
+		t.Errorf("Wait took %v; want the grandchild process to be killed promptly", elapsed)
+	}
+}
+
+func TestSignal(t *testing.T) {
+	cmd := moreexec.Command(exePath(), "-sleep=10m")
+	if err := cmd.Signal(os.Interrupt); err == nil {
+		t.Errorf("Signal before Start succeeded unexpectedly")
+	}
+
+	cmd = start(t, context.Background(), nil, 0, "-sleep=10m", "-interrupt")
+	if err := cmd.Signal(os.Interrupt); err != nil {
+		t.Fatalf("Signal(os.Interrupt): %v", err)
+	}
+	err := cmd.Wait()
+	t.Logf("stderr:\n%s", cmd.Stderr)
+	if err != nil {
+		t.Errorf("Wait: %v", err)
+	}
+	if !strings.Contains(cmd.Stderr.(*strings.Builder).String(), "received") {
+		t.Errorf("command did not report receiving the signal")
+	}
+
+	if err := cmd.Signal(os.Interrupt); !errors.Is(err, os.ErrProcessDone) {
+		t.Errorf("Signal after Wait: %v; want %v", err, os.ErrProcessDone)
+	}
+}
@@ -12,3 +12,16 @@ import (
 var quitSignal os.Signal = nil
 
 var errWindows error = syscall.EWINDOWS
+
+func setProcessGroup(attr *syscall.SysProcAttr) error {
+	attr.CreationFlags |= syscall.CREATE_NEW_PROCESS_GROUP
+	return nil
+}
+
+// signalGroup sends sig to the process p.
+//
+// Windows does not provide a way to signal an entire process group,
+// so other processes in the group are not affected.
+func signalGroup(p *os.Process, sig os.Signal) error {
+	return p.Signal(sig)
+}
@@ -0,0 +1,125 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreexec
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Pipeline runs cmds as a pipeline, like "a | b | c" in a shell: the standard
+// output of each command is connected to the standard input of the next using
+// an os.Pipe. Pipeline starts all of the commands, then waits for all of them
+// to complete.
+//
+// Every command except the last must have a nil Stdout, and every command
+// except the first must have a nil Stdin; Pipeline sets those fields to the
+// ends of the pipes it creates, so the commands cannot be restarted. Each
+// command's Context, Interrupt, Cancel, WaitDelay, and KillDelay apply to that
+// command as usual; when a command exits, the next one reads EOF from its
+// pipe, and the previous one gets an error (typically SIGPIPE) if it writes
+// more output.
+//
+// If any command fails to start, Pipeline kills and waits for the commands
+// that were already started. If any command fails, Pipeline returns a
+// *PipelineError describing every command that failed.
+func Pipeline(cmds ...*Cmd) error {
+	if len(cmds) == 0 {
+		return errors.New("moreexec: Pipeline requires at least one command")
+	}
+	for i, c := range cmds {
+		if i > 0 && (c.Stdin != nil || c.StdinBytes != nil) {
+			return fmt.Errorf("moreexec: Pipeline stage %d (%v): Stdin already set", i, c)
+		}
+		if i < len(cmds)-1 && c.Stdout != nil {
+			return fmt.Errorf("moreexec: Pipeline stage %d (%v): Stdout already set", i, c)
+		}
+	}
+
+	// The commands hold their own copies of the pipe descriptors, so close ours
+	// once they have all started (or failed to). Otherwise, the readers would
+	// never see EOF.
+	var pipes []*os.File
+	defer func() {
+		for _, f := range pipes {
+			f.Close()
+		}
+	}()
+	for i := 1; i < len(cmds); i++ {
+		r, w, err := os.Pipe()
+		if err != nil {
+			return err
+		}
+		pipes = append(pipes, r, w)
+		cmds[i-1].Stdout = w
+		cmds[i].Stdin = r
+	}
+
+	for i, c := range cmds {
+		if err := c.Start(); err != nil {
+			for _, started := range cmds[:i] {
+				started.Signal(os.Kill)
+				started.Wait()
+			}
+			return &PipelineError{Stages: []*StageError{{Stage: i, Cmd: c, Err: err}}}
+		}
+	}
+	for _, f := range pipes {
+		f.Close()
+	}
+	pipes = nil
+
+	var pe *PipelineError
+	for i, c := range cmds {
+		if err := c.Wait(); err != nil {
+			if pe == nil {
+				pe = new(PipelineError)
+			}
+			pe.Stages = append(pe.Stages, &StageError{Stage: i, Cmd: c, Err: err})
+		}
+	}
+	if pe != nil {
+		return pe
+	}
+	return nil
+}
+
+// A StageError reports the failure of one command in a pipeline.
+type StageError struct {
+	Stage int  // the index of the command in the pipeline
+	Cmd   *Cmd // the command that failed
+	Err   error
+}
+
+func (e *StageError) Error() string {
+	return fmt.Sprintf("stage %d (%v): %v", e.Stage, e.Cmd, e.Err)
+}
+
+func (e *StageError) Unwrap() error {
+	return e.Err
+}
+
+// A PipelineError reports the failure of one or more commands in a pipeline.
+type PipelineError struct {
+	Stages []*StageError // in pipeline order
+}
+
+func (e *PipelineError) Error() string {
+	msgs := make([]string, 0, len(e.Stages))
+	for _, s := range e.Stages {
+		msgs = append(msgs, s.Error())
+	}
+	return "moreexec: pipeline failed: " + strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the error from the last command in the pipeline that failed,
+// in the manner of a shell's "pipefail" option: when a later command exits
+// early, earlier commands often fail only as a consequence (for example, by
+// receiving SIGPIPE).
+func (e *PipelineError) Unwrap() error {
+	return e.Stages[len(e.Stages)-1].Err
+}
@@ -0,0 +1,80 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreexec_test
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/bcmills/more/os/moreexec"
+)
+
+func TestPipeline(t *testing.T) {
+	last := moreexec.Command(exePath(), "-readstdin")
+	stderr := new(strings.Builder)
+	last.Stderr = stderr
+
+	err := moreexec.Pipeline(
+		moreexec.Command(exePath(), "-stdout=hello"),
+		moreexec.Command(exePath(), "-cat"),
+		last,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "read 5 bytes from stdin: <nil>"; !strings.Contains(stderr.String(), want) {
+		t.Errorf("stderr of last stage:\n%s\nwant %q", stderr, want)
+	}
+}
+
+func TestPipelineFailure(t *testing.T) {
+	err := moreexec.Pipeline(
+		moreexec.Command(exePath(), "-stdout=hello"),
+		moreexec.Command(exePath(), "-stdout=partial", "-exit=3"),
+		moreexec.Command(exePath(), "-cat"),
+	)
+	t.Logf("Pipeline: %v", err)
+
+	var pe *moreexec.PipelineError
+	if !errors.As(err, &pe) {
+		t.Fatalf("Pipeline error = %v; want *PipelineError", err)
+	}
+	// The first stage may or may not fail, depending on whether it finishes
+	// writing before the second stage exits; the last stage should succeed.
+	if s := pe.Stages[len(pe.Stages)-1]; s.Stage != 1 {
+		t.Errorf("last failing stage = %d; want 1", s.Stage)
+	}
+
+	var ee *exec.ExitError
+	if !errors.As(err, &ee) || ee.ExitCode() != 3 {
+		t.Errorf("Pipeline error = %v; want *exec.ExitError with code 3", err)
+	}
+}
+
+func TestPipelineStartFailure(t *testing.T) {
+	first := moreexec.Command(exePath(), "-cat")
+	err := moreexec.Pipeline(
+		first,
+		moreexec.Command("moreexec-test-command-does-not-exist"),
+	)
+	t.Logf("Pipeline: %v", err)
+
+	if !errors.Is(err, exec.ErrNotFound) {
+		t.Errorf("Pipeline error = %v; want %v", err, exec.ErrNotFound)
+	}
+	if first.ProcessState == nil {
+		t.Errorf("Pipeline did not wait for the stage that started")
+	}
+}
+
+func TestPipelineStdoutSet(t *testing.T) {
+	first := moreexec.Command(exePath(), "-stdout=hello")
+	first.Stdout = new(strings.Builder)
+	if err := moreexec.Pipeline(first, moreexec.Command(exePath(), "-cat")); err == nil {
+		t.Errorf("Pipeline with Stdout already set succeeded unexpectedly")
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreexec
+
+import (
+	"os"
+	"strconv"
+	"syscall"
+	"unsafe"
+)
+
+func setControllingTTY(attr *syscall.SysProcAttr) error {
+	attr.Setsid = true
+	attr.Setctty = true
+	attr.Ctty = 0 // The terminal is the child's stdin.
+
+	// A session leader is already the leader of its own process group,
+	// and cannot be moved to another one.
+	attr.Setpgid = false
+	return nil
+}
+
+// openPTY allocates a pseudo-terminal, returning its controlling (“master”)
+// end and the terminal (“slave”) device to be passed to the child.
+func openPTY() (pty, tty *os.File, err error) {
+	pty, err = os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var n uint32
+	rc, err := pty.SyscallConn()
+	if err == nil {
+		ctlErr := rc.Control(func(fd uintptr) {
+			var unlock int32
+			if err = ioctl(fd, syscall.TIOCSPTLCK, unsafe.Pointer(&unlock)); err != nil {
+				return
+			}
+			err = ioctl(fd, syscall.TIOCGPTN, unsafe.Pointer(&n))
+		})
+		if err == nil {
+			err = ctlErr
+		}
+	}
+	if err != nil {
+		pty.Close()
+		return nil, nil, err
+	}
+
+	tty, err = os.OpenFile("/dev/pts/"+strconv.FormatUint(uint64(n), 10), os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		pty.Close()
+		return nil, nil, err
+	}
+	return pty, tty, nil
+}
+
+func ioctl(fd, req uintptr, arg unsafe.Pointer) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, req, uintptr(arg)); errno != 0 {
+		return os.NewSyscallError("ioctl", errno)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreexec_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/bcmills/more/os/moreexec"
+)
+
+func TestAllocatePTY(t *testing.T) {
+	cmd := moreexec.Command(exePath(), "-stdout=hello\n")
+	cmd.AllocatePTY = true
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	pty := cmd.PTY()
+	defer pty.Close()
+
+	outc := make(chan string)
+	go func() {
+		// Reading from the PTY fails (with EIO) once the command and all other
+		// holders of the terminal have closed it, so ignore the error.
+		b, _ := io.ReadAll(pty)
+		outc <- string(b)
+	}()
+
+	if err := cmd.Wait(); err != nil {
+		t.Errorf("Wait: %v", err)
+	}
+	out := <-outc
+
+	// The terminal's line discipline translates "\n" to "\r\n" on output,
+	// which a pipe would not do.
+	if want := "hello\r\n"; out != want {
+		t.Errorf("output = %q; want %q", out, want)
+	}
+}
+
+func TestAllocatePTYWithStdout(t *testing.T) {
+	cmd := moreexec.Command(exePath(), "-stdout=hello")
+	cmd.AllocatePTY = true
+	cmd.Stdout = new(strings.Builder)
+	if err := cmd.Start(); err == nil {
+		t.Errorf("Start succeeded unexpectedly")
+		cmd.Wait()
+	} else {
+		t.Logf("Start: %v", err)
+	}
+	if pty := cmd.PTY(); pty != nil {
+		t.Errorf("PTY() = %v after failed Start; want nil", pty)
+	}
+}
@@ -0,0 +1,23 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux
+// +build !linux
+
+package moreexec
+
+import (
+	"errors"
+	"os"
+	"runtime"
+	"syscall"
+)
+
+func setControllingTTY(attr *syscall.SysProcAttr) error {
+	return errors.New("moreexec: AllocatePTY is not supported on " + runtime.GOOS)
+}
+
+func openPTY() (pty, tty *os.File, err error) {
+	return nil, nil, errors.New("moreexec: AllocatePTY is not supported on " + runtime.GOOS)
+}
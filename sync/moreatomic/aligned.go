@@ -0,0 +1,45 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreatomic
+
+import (
+	"unsafe"
+)
+
+// Aligned64 allocates and returns a pointer to a new int64 (with value zero)
+// that is 8-byte aligned, and so is safe to access using the 64-bit functions
+// in sync/atomic even on 32-bit platforms such as GOARCH=386 and GOARCH=arm.
+//
+// Aligned64 is intended for existing structs whose int64 fields cannot easily
+// be moved to the start of the struct or changed to an Int64. To use it,
+// change the field from an int64 to a *int64 and initialize it with the result
+// of Aligned64 when the struct is constructed:
+//
+//	type stats struct {
+//		name  string
+//		count *int64 // accessed atomically; allocated by Aligned64
+//	}
+//
+//	s := &stats{name: name, count: moreatomic.Aligned64()}
+//	atomic.AddInt64(s.count, 1)
+//
+// For new code, prefer an Int64 field, which does not need a separate
+// allocation.
+func Aligned64() *int64 {
+	// The sync/atomic package guarantees that the first word in an allocated
+	// variable is 64-bit aligned.
+	return new(int64)
+}
+
+// CheckAlignment reports whether addr is 8-byte aligned, and thus safe to
+// access using the 64-bit functions in sync/atomic on all platforms.
+//
+// CheckAlignment is intended for use in tests and debug assertions, to detect
+// fields that would cause an "unaligned 64-bit atomic operation" panic on
+// 32-bit platforms before the code runs on one. (On 64-bit platforms, int64
+// fields are always 8-byte aligned, so CheckAlignment always reports true.)
+func CheckAlignment(addr *int64) bool {
+	return uintptr(unsafe.Pointer(addr))%8 == 0
+}
@@ -0,0 +1,44 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreatomic_test
+
+import (
+	"sync/atomic"
+	"testing"
+	"unsafe"
+
+	"github.com/bcmills/more/sync/moreatomic"
+)
+
+func TestAligned64(t *testing.T) {
+	// On 32-bit platforms, the leading int32 field would misalign a plain int64
+	// field, but not the int64 that it points to.
+	type stats struct {
+		_     int32
+		count *int64
+	}
+
+	for i := 0; i < 100; i++ {
+		s := &stats{count: moreatomic.Aligned64()}
+		if !moreatomic.CheckAlignment(s.count) {
+			t.Fatalf("Aligned64() = %p; not 8-byte aligned", s.count)
+		}
+		if got := atomic.AddInt64(s.count, 1); got != 1 {
+			t.Fatalf("AddInt64(Aligned64(), 1) = %v; want 1", got)
+		}
+	}
+}
+
+func TestCheckAlignment(t *testing.T) {
+	var a [2]int64
+	p := unsafe.Pointer(&a[0])
+
+	if !moreatomic.CheckAlignment((*int64)(p)) {
+		t.Errorf("CheckAlignment(&a[0]) = false; want true")
+	}
+	if moreatomic.CheckAlignment((*int64)(unsafe.Pointer(uintptr(p) + 4))) {
+		t.Errorf("CheckAlignment(&a[0] + 4) = true; want false")
+	}
+}
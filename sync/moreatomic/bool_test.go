@@ -0,0 +1,65 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreatomic_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/bcmills/more/sync/moreatomic"
+)
+
+func TestBool(t *testing.T) {
+	var x moreatomic.Bool
+	if x.Load() {
+		t.Fatalf("zero Bool: Load() = true")
+	}
+
+	x.Store(true)
+	if !x.Load() {
+		t.Fatalf("after Store(true): Load() = false")
+	}
+	if old := x.Swap(false); !old {
+		t.Fatalf("Swap(false) = false; want true")
+	}
+	if x.CompareAndSwap(true, false) {
+		t.Fatalf("CompareAndSwap(true, false) succeeded with value false")
+	}
+	if !x.CompareAndSwap(false, true) || !x.Load() {
+		t.Fatalf("CompareAndSwap(false, true) failed with value false")
+	}
+}
+
+func TestBoolConcurrentToggle(t *testing.T) {
+	const (
+		goroutines = 8
+		toggles    = 1000
+	)
+
+	var (
+		x  moreatomic.Bool
+		wg sync.WaitGroup
+	)
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < toggles; j++ {
+				for {
+					old := x.Load()
+					if x.CompareAndSwap(old, !old) {
+						break
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// An even number of successful toggles must leave the value unchanged.
+	if x.Load() {
+		t.Fatalf("after %d toggles: Load() = true; want false", goroutines*toggles)
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreatomic
+
+// A Counter is an atomic int64 counter, suitable for metrics that are
+// periodically collected and reset. The zero value is zero.
+//
+// Like Int64, a Counter is safe to use on 32-bit platforms regardless of its
+// position within a struct.
+//
+// A Counter must not be copied after first use.
+type Counter struct {
+	n Int64
+}
+
+// Inc atomically adds 1 to c.
+func (c *Counter) Inc() { c.n.Add(1) }
+
+// Dec atomically subtracts 1 from c.
+func (c *Counter) Dec() { c.n.Add(-1) }
+
+// Add atomically adds delta to c and returns the new value.
+func (c *Counter) Add(delta int64) (new int64) { return c.n.Add(delta) }
+
+// Value atomically loads and returns the current value of c.
+func (c *Counter) Value() int64 { return c.n.Load() }
+
+// Flush atomically resets c to zero and returns its previous value.
+// No concurrent Inc, Dec, or Add is lost: each is counted either in the
+// value returned by Flush or in the value after it.
+func (c *Counter) Flush() int64 { return c.n.Swap(0) }
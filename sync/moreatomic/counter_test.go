@@ -0,0 +1,76 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreatomic_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/bcmills/more/sync/moreatomic"
+)
+
+func TestCounter(t *testing.T) {
+	// The leading int32 field misaligns the counter on 32-bit platforms.
+	var s struct {
+		_ int32
+		c moreatomic.Counter
+	}
+
+	s.c.Inc()
+	s.c.Inc()
+	s.c.Dec()
+	if got := s.c.Add(41); got != 42 {
+		t.Fatalf("Add(41) = %v; want 42", got)
+	}
+	if got := s.c.Value(); got != 42 {
+		t.Fatalf("Value() = %v; want 42", got)
+	}
+	if got := s.c.Flush(); got != 42 {
+		t.Fatalf("Flush() = %v; want 42", got)
+	}
+	if got := s.c.Value(); got != 0 {
+		t.Fatalf("after Flush: Value() = %v; want 0", got)
+	}
+}
+
+func TestCounterFlushConcurrent(t *testing.T) {
+	var (
+		c       moreatomic.Counter
+		wg      sync.WaitGroup
+		flushed int64
+	)
+
+	const (
+		goroutines = 8
+		increments = 1000
+	)
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < increments; j++ {
+				c.Inc()
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	for {
+		flushed += c.Flush()
+		select {
+		case <-done:
+			flushed += c.Flush()
+			if flushed != goroutines*increments {
+				t.Errorf("total flushed = %v; want %v", flushed, goroutines*increments)
+			}
+			return
+		default:
+		}
+	}
+}
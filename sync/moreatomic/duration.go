@@ -0,0 +1,35 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreatomic
+
+import (
+	"time"
+)
+
+// A Duration is an atomic time.Duration. The zero value is zero.
+//
+// Like Int64, a Duration is safe to use on 32-bit platforms regardless of its
+// position within a struct.
+//
+// A Duration must not be copied after first use.
+type Duration struct {
+	v Int64
+}
+
+// Load atomically loads and returns the value stored in x.
+func (x *Duration) Load() time.Duration { return time.Duration(x.v.Load()) }
+
+// Store atomically stores val into x.
+func (x *Duration) Store(val time.Duration) { x.v.Store(int64(val)) }
+
+// Swap atomically stores new into x and returns the previous value.
+func (x *Duration) Swap(new time.Duration) (old time.Duration) {
+	return time.Duration(x.v.Swap(int64(new)))
+}
+
+// CompareAndSwap executes the compare-and-swap operation for x.
+func (x *Duration) CompareAndSwap(old, new time.Duration) (swapped bool) {
+	return x.v.CompareAndSwap(int64(old), int64(new))
+}
@@ -0,0 +1,36 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreatomic_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bcmills/more/sync/moreatomic"
+)
+
+func TestDuration(t *testing.T) {
+	var config struct {
+		_       bool
+		timeout moreatomic.Duration
+	}
+
+	if d := config.timeout.Load(); d != 0 {
+		t.Fatalf("zero Duration: Load() = %v", d)
+	}
+	config.timeout.Store(5 * time.Second)
+	if old := config.timeout.Swap(time.Minute); old != 5*time.Second {
+		t.Fatalf("Swap(1m) = %v; want 5s", old)
+	}
+	if config.timeout.CompareAndSwap(time.Second, time.Hour) {
+		t.Fatalf("CompareAndSwap(1s, 1h) succeeded with value 1m")
+	}
+	if !config.timeout.CompareAndSwap(time.Minute, time.Hour) {
+		t.Fatalf("CompareAndSwap(1m, 1h) failed with value 1m")
+	}
+	if d := config.timeout.Load(); d != time.Hour {
+		t.Fatalf("Load() = %v; want 1h", d)
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreatomic
+
+import (
+	"math"
+	"sync/atomic"
+	"unsafe"
+)
+
+// AddFloat64 atomically adds delta to *addr and returns the new value.
+//
+// AddFloat64 is implemented as a compare-and-swap loop, so under heavy
+// contention it may retry many times before succeeding.
+func AddFloat64(addr *float64, delta float64) (new float64) {
+	p := (*uint64)(unsafe.Pointer(addr))
+	for {
+		old := atomic.LoadUint64(p)
+		new = math.Float64frombits(old) + delta
+		if atomic.CompareAndSwapUint64(p, old, math.Float64bits(new)) {
+			return new
+		}
+	}
+}
+
+func LoadFloat64(addr *float64) (val float64) {
+	return math.Float64frombits(atomic.LoadUint64((*uint64)(unsafe.Pointer(addr))))
+}
+
+func StoreFloat64(addr *float64, val float64) {
+	atomic.StoreUint64((*uint64)(unsafe.Pointer(addr)), math.Float64bits(val))
+}
+
+// AddFloat32 atomically adds delta to *addr and returns the new value.
+//
+// AddFloat32 is implemented as a compare-and-swap loop, so under heavy
+// contention it may retry many times before succeeding.
+func AddFloat32(addr *float32, delta float32) (new float32) {
+	p := (*uint32)(unsafe.Pointer(addr))
+	for {
+		old := atomic.LoadUint32(p)
+		new = math.Float32frombits(old) + delta
+		if atomic.CompareAndSwapUint32(p, old, math.Float32bits(new)) {
+			return new
+		}
+	}
+}
+
+func LoadFloat32(addr *float32) (val float32) {
+	return math.Float32frombits(atomic.LoadUint32((*uint32)(unsafe.Pointer(addr))))
+}
+
+func StoreFloat32(addr *float32, val float32) {
+	atomic.StoreUint32((*uint32)(unsafe.Pointer(addr)), math.Float32bits(val))
+}
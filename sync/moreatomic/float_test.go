@@ -0,0 +1,55 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreatomic_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/bcmills/more/sync/moreatomic"
+)
+
+func TestAddFloatConcurrent(t *testing.T) {
+	const (
+		goroutines = 8
+		adds       = 1000
+	)
+
+	var (
+		f64 float64
+		f32 float32
+		wg  sync.WaitGroup
+	)
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < adds; j++ {
+				// Halves are exactly representable,
+				// so the sum does not depend on the order of additions.
+				moreatomic.AddFloat64(&f64, 0.5)
+				moreatomic.AddFloat32(&f32, 0.5)
+			}
+		}()
+	}
+	wg.Wait()
+
+	const want = goroutines * adds * 0.5
+	if got := moreatomic.LoadFloat64(&f64); got != want {
+		t.Errorf("LoadFloat64 = %v; want %v", got, want)
+	}
+	if got := moreatomic.LoadFloat32(&f32); got != want {
+		t.Errorf("LoadFloat32 = %v; want %v", got, want)
+	}
+
+	moreatomic.StoreFloat64(&f64, -1.25)
+	moreatomic.StoreFloat32(&f32, -1.25)
+	if got := moreatomic.AddFloat64(&f64, 0.25); got != -1 {
+		t.Errorf("after StoreFloat64(-1.25): AddFloat64(0.25) = %v; want -1", got)
+	}
+	if got := moreatomic.AddFloat32(&f32, 0.25); got != -1 {
+		t.Errorf("after StoreFloat32(-1.25): AddFloat32(0.25) = %v; want -1", got)
+	}
+}
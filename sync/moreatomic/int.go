@@ -65,6 +65,13 @@ func SwapInt(addr *int, new int) (old int) {
 	}
 }
 
+// ResetInt atomically stores 0 into *addr and returns the previous value.
+// It is equivalent to SwapInt(addr, 0), but is named for its intended use:
+// atomically reading and clearing a counter, such as when flushing metrics.
+func ResetInt(addr *int) (old int) {
+	return SwapInt(addr, 0)
+}
+
 func AddUint(addr *uint, delta uint) (new uint) {
 	switch unsafe.Sizeof(*addr) {
 	case 4:
@@ -119,3 +126,180 @@ func SwapUint(addr *uint, new uint) (old uint) {
 		panic("uint is neither 4 nor 8 bytes")
 	}
 }
+
+// ResetUint atomically stores 0 into *addr and returns the previous value.
+// It is equivalent to SwapUint(addr, 0).
+func ResetUint(addr *uint) (old uint) {
+	return SwapUint(addr, 0)
+}
+
+// AddIntChecked is like AddInt, but also reports whether the addition
+// overflowed. If it did, *addr is left with the wrapped-around result,
+// as with AddInt.
+func AddIntChecked(addr *int, delta int) (new int, overflow bool) {
+	new = AddInt(addr, delta)
+	old := new - delta
+	if delta > 0 {
+		return new, new < old
+	}
+	return new, new > old
+}
+
+// AddUintChecked is like AddUint, but also reports whether the addition
+// wrapped around (that is, carried out of the most significant bit).
+// If it did, *addr is left with the wrapped-around result, as with AddUint.
+//
+// Note that subtracting c via AddUintChecked(addr, ^uint(c-1)) reports a
+// wraparound unless the subtraction underflows.
+func AddUintChecked(addr *uint, delta uint) (new uint, overflow bool) {
+	new = AddUint(addr, delta)
+	return new, new < delta
+}
+
+func AndInt(addr *int, mask int) (old int) {
+	switch unsafe.Sizeof(*addr) {
+	case 4:
+		p := (*int32)(unsafe.Pointer(addr))
+		for {
+			o := atomic.LoadInt32(p)
+			if atomic.CompareAndSwapInt32(p, o, o&int32(mask)) {
+				return int(o)
+			}
+		}
+	case 8:
+		p := (*int64)(unsafe.Pointer(addr))
+		for {
+			o := atomic.LoadInt64(p)
+			if atomic.CompareAndSwapInt64(p, o, o&int64(mask)) {
+				return int(o)
+			}
+		}
+	default:
+		panic("int is neither 4 nor 8 bytes")
+	}
+}
+
+func OrInt(addr *int, mask int) (old int) {
+	switch unsafe.Sizeof(*addr) {
+	case 4:
+		p := (*int32)(unsafe.Pointer(addr))
+		for {
+			o := atomic.LoadInt32(p)
+			if atomic.CompareAndSwapInt32(p, o, o|int32(mask)) {
+				return int(o)
+			}
+		}
+	case 8:
+		p := (*int64)(unsafe.Pointer(addr))
+		for {
+			o := atomic.LoadInt64(p)
+			if atomic.CompareAndSwapInt64(p, o, o|int64(mask)) {
+				return int(o)
+			}
+		}
+	default:
+		panic("int is neither 4 nor 8 bytes")
+	}
+}
+
+func AndUint(addr *uint, mask uint) (old uint) {
+	switch unsafe.Sizeof(*addr) {
+	case 4:
+		p := (*uint32)(unsafe.Pointer(addr))
+		for {
+			o := atomic.LoadUint32(p)
+			if atomic.CompareAndSwapUint32(p, o, o&uint32(mask)) {
+				return uint(o)
+			}
+		}
+	case 8:
+		p := (*uint64)(unsafe.Pointer(addr))
+		for {
+			o := atomic.LoadUint64(p)
+			if atomic.CompareAndSwapUint64(p, o, o&uint64(mask)) {
+				return uint(o)
+			}
+		}
+	default:
+		panic("uint is neither 4 nor 8 bytes")
+	}
+}
+
+func OrUint(addr *uint, mask uint) (old uint) {
+	switch unsafe.Sizeof(*addr) {
+	case 4:
+		p := (*uint32)(unsafe.Pointer(addr))
+		for {
+			o := atomic.LoadUint32(p)
+			if atomic.CompareAndSwapUint32(p, o, o|uint32(mask)) {
+				return uint(o)
+			}
+		}
+	case 8:
+		p := (*uint64)(unsafe.Pointer(addr))
+		for {
+			o := atomic.LoadUint64(p)
+			if atomic.CompareAndSwapUint64(p, o, o|uint64(mask)) {
+				return uint(o)
+			}
+		}
+	default:
+		panic("uint is neither 4 nor 8 bytes")
+	}
+}
+
+// StoreMaxInt atomically sets *addr to the maximum of its current value and
+// val, and returns the resulting value.
+func StoreMaxInt(addr *int, val int) (new int) {
+	for {
+		old := LoadInt(addr)
+		if old >= val {
+			return old
+		}
+		if CompareAndSwapInt(addr, old, val) {
+			return val
+		}
+	}
+}
+
+// StoreMinInt atomically sets *addr to the minimum of its current value and
+// val, and returns the resulting value.
+func StoreMinInt(addr *int, val int) (new int) {
+	for {
+		old := LoadInt(addr)
+		if old <= val {
+			return old
+		}
+		if CompareAndSwapInt(addr, old, val) {
+			return val
+		}
+	}
+}
+
+// StoreMaxUint atomically sets *addr to the maximum of its current value and
+// val, and returns the resulting value.
+func StoreMaxUint(addr *uint, val uint) (new uint) {
+	for {
+		old := LoadUint(addr)
+		if old >= val {
+			return old
+		}
+		if CompareAndSwapUint(addr, old, val) {
+			return val
+		}
+	}
+}
+
+// StoreMinUint atomically sets *addr to the minimum of its current value and
+// val, and returns the resulting value.
+func StoreMinUint(addr *uint, val uint) (new uint) {
+	for {
+		old := LoadUint(addr)
+		if old <= val {
+			return old
+		}
+		if CompareAndSwapUint(addr, old, val) {
+			return val
+		}
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreatomic_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/bcmills/more/sync/moreatomic"
+)
+
+func TestInt32(t *testing.T) {
+	var i moreatomic.Int32
+	if got := i.Load(); got != 0 {
+		t.Fatalf("zero Int32: Load() = %v", got)
+	}
+	i.Store(math.MaxInt32)
+	if got := i.Add(1); got != math.MinInt32 {
+		t.Fatalf("Add(1) = %v; want %v (wrapped)", got, math.MinInt32)
+	}
+	if old := i.Swap(-5); old != math.MinInt32 {
+		t.Fatalf("Swap(-5) = %v; want %v", old, math.MinInt32)
+	}
+	if i.CompareAndSwap(0, 1) || !i.CompareAndSwap(-5, 7) || i.Load() != 7 {
+		t.Fatalf("CompareAndSwap did not behave as expected")
+	}
+}
+
+func TestUint32(t *testing.T) {
+	var u moreatomic.Uint32
+	if got := u.Load(); got != 0 {
+		t.Fatalf("zero Uint32: Load() = %v", got)
+	}
+	if got := u.Add(^uint32(0)); got != math.MaxUint32 {
+		t.Fatalf("Add(^uint32(0)) = %v; want %v (wrapped)", got, uint32(math.MaxUint32))
+	}
+	if got := u.Add(1); got != 0 {
+		t.Fatalf("Add(1) = %v; want 0 (wrapped)", got)
+	}
+	if old := u.Swap(5); old != 0 {
+		t.Fatalf("Swap(5) = %v; want 0", old)
+	}
+	if u.CompareAndSwap(0, 1) || !u.CompareAndSwap(5, 7) || u.Load() != 7 {
+		t.Fatalf("CompareAndSwap did not behave as expected")
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreatomic
+
+import (
+	"sync/atomic"
+	"unsafe"
+)
+
+// An Int64 is an atomic int64. The zero value is zero.
+//
+// Unlike a plain int64 field accessed using the sync/atomic functions, an Int64
+// is safe to use on 32-bit platforms (such as GOARCH=386 and GOARCH=arm) even
+// if it is not the first word in an allocated struct: it reserves enough space
+// to locate an 8-byte-aligned word regardless of its own alignment.
+//
+// An Int64 must not be copied after first use.
+type Int64 struct {
+	v aligned64
+}
+
+// Load atomically loads and returns the value stored in x.
+func (x *Int64) Load() int64 { return atomic.LoadInt64(x.v.int64()) }
+
+// Store atomically stores val into x.
+func (x *Int64) Store(val int64) { atomic.StoreInt64(x.v.int64(), val) }
+
+// Swap atomically stores new into x and returns the previous value.
+func (x *Int64) Swap(new int64) (old int64) { return atomic.SwapInt64(x.v.int64(), new) }
+
+// CompareAndSwap executes the compare-and-swap operation for x.
+func (x *Int64) CompareAndSwap(old, new int64) (swapped bool) {
+	return atomic.CompareAndSwapInt64(x.v.int64(), old, new)
+}
+
+// Add atomically adds delta to x and returns the new value.
+func (x *Int64) Add(delta int64) (new int64) { return atomic.AddInt64(x.v.int64(), delta) }
+
+// A Uint64 is an atomic uint64. The zero value is zero.
+//
+// Like Int64, a Uint64 is safe to use on 32-bit platforms regardless of its
+// position within a struct.
+//
+// A Uint64 must not be copied after first use.
+type Uint64 struct {
+	v aligned64
+}
+
+// Load atomically loads and returns the value stored in x.
+func (x *Uint64) Load() uint64 { return atomic.LoadUint64(x.v.uint64()) }
+
+// Store atomically stores val into x.
+func (x *Uint64) Store(val uint64) { atomic.StoreUint64(x.v.uint64(), val) }
+
+// Swap atomically stores new into x and returns the previous value.
+func (x *Uint64) Swap(new uint64) (old uint64) { return atomic.SwapUint64(x.v.uint64(), new) }
+
+// CompareAndSwap executes the compare-and-swap operation for x.
+func (x *Uint64) CompareAndSwap(old, new uint64) (swapped bool) {
+	return atomic.CompareAndSwapUint64(x.v.uint64(), old, new)
+}
+
+// Add atomically adds delta to x and returns the new value.
+func (x *Uint64) Add(delta uint64) (new uint64) { return atomic.AddUint64(x.v.uint64(), delta) }
+
+// aligned64 contains a 64-bit word that is 8-byte aligned even if the
+// aligned64 itself is only 4-byte aligned, using the same technique as
+// sync.WaitGroup prior to Go 1.20.
+type aligned64 [3]uint32
+
+func (a *aligned64) ptr() unsafe.Pointer {
+	if uintptr(unsafe.Pointer(a))%8 == 0 {
+		return unsafe.Pointer(&a[0])
+	}
+	return unsafe.Pointer(&a[1])
+}
+
+func (a *aligned64) int64() *int64   { return (*int64)(a.ptr()) }
+func (a *aligned64) uint64() *uint64 { return (*uint64)(a.ptr()) }
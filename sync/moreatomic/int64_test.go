@@ -0,0 +1,76 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreatomic_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/bcmills/more/sync/moreatomic"
+)
+
+func TestInt64(t *testing.T) {
+	// On 32-bit platforms, the leading int32 field causes the counters to be
+	// only 4-byte aligned, which would cause a plain int64 to panic when
+	// accessed atomically.
+	var s struct {
+		_ int32
+		i moreatomic.Int64
+		_ int32
+		u moreatomic.Uint64
+	}
+
+	if got := s.i.Load(); got != 0 {
+		t.Fatalf("zero Int64: Load() = %v", got)
+	}
+	s.i.Store(1 << 40)
+	if got := s.i.Add(-1); got != 1<<40-1 {
+		t.Fatalf("Add(-1) = %v; want %v", got, int64(1<<40-1))
+	}
+	if old := s.i.Swap(-5); old != 1<<40-1 {
+		t.Fatalf("Swap(-5) = %v; want %v", old, int64(1<<40-1))
+	}
+	if s.i.CompareAndSwap(0, 1) || !s.i.CompareAndSwap(-5, 7) || s.i.Load() != 7 {
+		t.Fatalf("CompareAndSwap did not behave as expected")
+	}
+
+	if got := s.u.Load(); got != 0 {
+		t.Fatalf("zero Uint64: Load() = %v", got)
+	}
+	s.u.Store(1 << 63)
+	if got := s.u.Add(^uint64(0)); got != 1<<63-1 {
+		t.Fatalf("Add(^uint64(0)) = %v; want %v", got, uint64(1<<63-1))
+	}
+	if old := s.u.Swap(5); old != 1<<63-1 {
+		t.Fatalf("Swap(5) = %v; want %v", old, uint64(1<<63-1))
+	}
+	if s.u.CompareAndSwap(0, 1) || !s.u.CompareAndSwap(5, 7) || s.u.Load() != 7 {
+		t.Fatalf("CompareAndSwap did not behave as expected")
+	}
+}
+
+func TestInt64Concurrent(t *testing.T) {
+	var (
+		counter struct {
+			_ byte
+			n moreatomic.Int64
+		}
+		wg sync.WaitGroup
+	)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				counter.n.Add(1 << 32)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := counter.n.Load(); got != 8000<<32 {
+		t.Fatalf("Load() = %v; want %v", got, int64(8000<<32))
+	}
+}
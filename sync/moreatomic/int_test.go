@@ -0,0 +1,163 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreatomic_test
+
+import (
+	"math/bits"
+	"sync"
+	"testing"
+
+	"github.com/bcmills/more/sync/moreatomic"
+)
+
+func TestStoreMaxMinConcurrent(t *testing.T) {
+	var (
+		maxInt, minInt   = 0, 0
+		maxUint, minUint = uint(0), ^uint(0)
+		wg               sync.WaitGroup
+	)
+	for i := -100; i <= 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			moreatomic.StoreMaxInt(&maxInt, i)
+			moreatomic.StoreMinInt(&minInt, i)
+			if i >= 0 {
+				moreatomic.StoreMaxUint(&maxUint, uint(i))
+				moreatomic.StoreMinUint(&minUint, uint(i)+1)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if got := moreatomic.LoadInt(&maxInt); got != 100 {
+		t.Errorf("max int = %v; want 100", got)
+	}
+	if got := moreatomic.LoadInt(&minInt); got != -100 {
+		t.Errorf("min int = %v; want -100", got)
+	}
+	if got := moreatomic.LoadUint(&maxUint); got != 100 {
+		t.Errorf("max uint = %v; want 100", got)
+	}
+	if got := moreatomic.LoadUint(&minUint); got != 1 {
+		t.Errorf("min uint = %v; want 1", got)
+	}
+
+	if got := moreatomic.StoreMaxInt(&maxInt, 50); got != 100 {
+		t.Errorf("StoreMaxInt(&maxInt, 50) = %v; want 100", got)
+	}
+	if got := moreatomic.StoreMinUint(&minUint, 0); got != 0 {
+		t.Errorf("StoreMinUint(&minUint, 0) = %v; want 0", got)
+	}
+}
+
+func TestAndOr(t *testing.T) {
+	i := 0b1100
+	if old := moreatomic.AndInt(&i, 0b1010); old != 0b1100 || i != 0b1000 {
+		t.Errorf("AndInt(&0b1100, 0b1010) = %#b, leaving %#b; want 0b1100, leaving 0b1000", old, i)
+	}
+	if old := moreatomic.OrInt(&i, 0b0011); old != 0b1000 || i != 0b1011 {
+		t.Errorf("OrInt(&0b1000, 0b0011) = %#b, leaving %#b; want 0b1000, leaving 0b1011", old, i)
+	}
+
+	u := ^uint(0)
+	if old := moreatomic.AndUint(&u, 1); old != ^uint(0) || u != 1 {
+		t.Errorf("AndUint(&^uint(0), 1) = %#x, leaving %#x; want %#x, leaving 1", old, u, ^uint(0))
+	}
+	if old := moreatomic.OrUint(&u, 1<<(bits.UintSize-1)); old != 1 || u != 1<<(bits.UintSize-1)|1 {
+		t.Errorf("OrUint(&1, high bit) = %#x, leaving %#x", old, u)
+	}
+}
+
+func TestOrConcurrent(t *testing.T) {
+	var (
+		flags uint
+		wg    sync.WaitGroup
+	)
+	for i := 0; i < bits.UintSize; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			moreatomic.OrUint(&flags, 1<<i)
+		}(i)
+	}
+	wg.Wait()
+
+	if got := moreatomic.LoadUint(&flags); got != ^uint(0) {
+		t.Errorf("after setting all bits: %#x; want %#x", got, ^uint(0))
+	}
+}
+
+func TestAddChecked(t *testing.T) {
+	const (
+		maxInt = 1<<(bits.UintSize-1) - 1
+		minInt = -maxInt - 1
+	)
+
+	for _, tc := range []struct {
+		x, delta     int
+		want         int
+		wantOverflow bool
+	}{
+		{0, 1, 1, false},
+		{maxInt - 1, 1, maxInt, false},
+		{maxInt, 1, minInt, true},
+		{minInt + 1, -1, minInt, false},
+		{minInt, -1, maxInt, true},
+		{-1, 0, -1, false},
+	} {
+		x := tc.x
+		got, overflow := moreatomic.AddIntChecked(&x, tc.delta)
+		if got != tc.want || overflow != tc.wantOverflow || x != tc.want {
+			t.Errorf("AddIntChecked(&%v, %v) = %v, %v; want %v, %v", tc.x, tc.delta, got, overflow, tc.want, tc.wantOverflow)
+		}
+	}
+
+	u := ^uint(0) - 1
+	if got, overflow := moreatomic.AddUintChecked(&u, 1); got != ^uint(0) || overflow {
+		t.Errorf("AddUintChecked(&(^uint(0)-1), 1) = %v, %v; want %v, false", got, overflow, ^uint(0))
+	}
+	if got, overflow := moreatomic.AddUintChecked(&u, 2); got != 1 || !overflow {
+		t.Errorf("AddUintChecked(&^uint(0), 2) = %v, %v; want 1, true", got, overflow)
+	}
+}
+
+func TestReset(t *testing.T) {
+	var (
+		n  int
+		u  uint
+		wg sync.WaitGroup
+	)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				moreatomic.AddInt(&n, 1)
+				moreatomic.AddUint(&u, 1)
+			}
+		}()
+	}
+
+	var totalN, totalU int
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	for flushing := true; flushing; {
+		select {
+		case <-done:
+			flushing = false
+		default:
+		}
+		totalN += moreatomic.ResetInt(&n)
+		totalU += int(moreatomic.ResetUint(&u))
+	}
+
+	if totalN != 800 || totalU != 800 {
+		t.Errorf("flushed totals = %v, %v; want 800, 800", totalN, totalU)
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreatomic
+
+import (
+	"sync"
+)
+
+// A LazyInt is an int that is computed on first use and cached thereafter.
+// The zero value is uninitialized.
+//
+// A LazyInt must not be copied after first use.
+type LazyInt struct {
+	done Bool
+	once sync.Once
+	v    int
+}
+
+// Get returns the value of l, first calling init to compute it if l has not
+// yet been initialized. If multiple goroutines call Get concurrently before l
+// is initialized, exactly one of them calls init and the others wait for it to
+// return.
+//
+// After l has been initialized, Get requires only an atomic load.
+func (l *LazyInt) Get(init func() int) int {
+	if !l.done.Load() {
+		l.once.Do(func() {
+			StoreInt(&l.v, init())
+			l.done.Store(true)
+		})
+	}
+	return LoadInt(&l.v)
+}
+
+// Load returns the value of l and true if l has been initialized,
+// or 0 and false otherwise. Load never calls an init function.
+func (l *LazyInt) Load() (val int, ok bool) {
+	if !l.done.Load() {
+		return 0, false
+	}
+	return LoadInt(&l.v), true
+}
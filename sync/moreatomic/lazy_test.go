@@ -0,0 +1,48 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreatomic_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/bcmills/more/sync/moreatomic"
+)
+
+func TestLazyInt(t *testing.T) {
+	var l moreatomic.LazyInt
+	if v, ok := l.Load(); ok {
+		t.Fatalf("zero LazyInt: Load() = %v, true; want 0, false", v)
+	}
+
+	var (
+		calls moreatomic.Int32
+		wg    sync.WaitGroup
+	)
+	init := func() int {
+		calls.Add(1)
+		return 42
+	}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v := l.Get(init); v != 42 {
+				t.Errorf("Get(init) = %v; want 42", v)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("init called %d times; want 1", n)
+	}
+	if v, ok := l.Load(); v != 42 || !ok {
+		t.Errorf("Load() = %v, %v; want 42, true", v, ok)
+	}
+	if v := l.Get(func() int { return 0 }); v != 42 {
+		t.Errorf("Get(…) after initialization = %v; want 42", v)
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.18
+// +build go1.18
+
+package moreatomic_test
+
+import (
+	"testing"
+
+	"github.com/bcmills/more/sync/moreatomic"
+)
+
+func testPointer[T any](t *testing.T, a, b *T) {
+	t.Helper()
+
+	var x moreatomic.Pointer[T]
+	if p := x.Load(); p != nil {
+		t.Fatalf("zero Pointer[%T]: Load() = %p; want nil", *a, p)
+	}
+
+	x.Store(a)
+	if p := x.Load(); p != a {
+		t.Fatalf("after Store(a): Load() = %p; want %p", p, a)
+	}
+	if old := x.Swap(b); old != a {
+		t.Fatalf("Swap(b) = %p; want %p", old, a)
+	}
+	if x.CompareAndSwap(a, nil) {
+		t.Fatalf("CompareAndSwap(a, nil) succeeded with value b")
+	}
+	if !x.CompareAndSwap(b, nil) || x.Load() != nil {
+		t.Fatalf("CompareAndSwap(b, nil) failed with value b")
+	}
+}
+
+func TestPointer(t *testing.T) {
+	t.Run("int", func(t *testing.T) {
+		a, b := 1, 2
+		testPointer(t, &a, &b)
+	})
+	t.Run("string", func(t *testing.T) {
+		a, b := "a", "b"
+		testPointer(t, &a, &b)
+	})
+	t.Run("struct", func(t *testing.T) {
+		type pair struct{ x, y int }
+		testPointer(t, &pair{1, 2}, &pair{3, 4})
+	})
+}
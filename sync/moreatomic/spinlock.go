@@ -0,0 +1,59 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreatomic
+
+import (
+	"runtime"
+)
+
+// spinlockIterations is the number of times Lock retries its compare-and-swap
+// before yielding the processor.
+const spinlockIterations = 64
+
+// A Spinlock is a mutual exclusion lock that busy-waits instead of parking the
+// waiting goroutine. The zero value is an unlocked Spinlock.
+//
+// A Spinlock is only appropriate for protecting very short critical sections
+// with little contention, such as updating a few words of memory. It must not
+// be held across any call that may block — including channel operations, I/O,
+// system calls, and acquiring other locks — because goroutines waiting for it
+// continue to consume CPU time, and may prevent the holder from being scheduled
+// at all. In any other situation, use sync.Mutex instead.
+//
+// A Spinlock must not be copied after first use.
+type Spinlock struct {
+	state int
+}
+
+// Lock locks l. If the lock is already in use, the calling goroutine spins
+// until it becomes available, periodically yielding the processor to allow
+// the holder of the lock to run.
+func (l *Spinlock) Lock() {
+	for {
+		for i := 0; i < spinlockIterations; i++ {
+			if LoadInt(&l.state) == 0 && CompareAndSwapInt(&l.state, 0, 1) {
+				return
+			}
+		}
+		runtime.Gosched()
+	}
+}
+
+// TryLock tries to lock l and reports whether it succeeded.
+// TryLock never spins or blocks.
+func (l *Spinlock) TryLock() bool {
+	return CompareAndSwapInt(&l.state, 0, 1)
+}
+
+// Unlock unlocks l.
+// It is a run-time error if l is not locked on entry to Unlock.
+//
+// As with sync.Mutex, a locked Spinlock is not associated with a particular
+// goroutine: one goroutine may lock it and arrange for another to unlock it.
+func (l *Spinlock) Unlock() {
+	if !CompareAndSwapInt(&l.state, 1, 0) {
+		panic("moreatomic: unlock of unlocked Spinlock")
+	}
+}
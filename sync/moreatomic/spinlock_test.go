@@ -0,0 +1,90 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreatomic_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/bcmills/more/sync/moreatomic"
+)
+
+func TestSpinlock(t *testing.T) {
+	var (
+		l moreatomic.Spinlock
+		n int
+	)
+
+	if !l.TryLock() {
+		t.Fatalf("TryLock on unlocked Spinlock failed")
+	}
+	if l.TryLock() {
+		t.Fatalf("TryLock on locked Spinlock succeeded")
+	}
+	l.Unlock()
+
+	const (
+		goroutines = 8
+		iterations = 1000
+	)
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < iterations; j++ {
+				l.Lock()
+				n++
+				l.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n != goroutines*iterations {
+		t.Errorf("n = %v; want %v", n, goroutines*iterations)
+	}
+}
+
+func TestSpinlockUnlockOfUnlocked(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Unlock of unlocked Spinlock did not panic")
+		}
+	}()
+
+	var l moreatomic.Spinlock
+	l.Unlock()
+}
+
+func BenchmarkSpinlock(b *testing.B) {
+	var l moreatomic.Spinlock
+	benchmarkLocker(b, &l)
+}
+
+func BenchmarkMutex(b *testing.B) {
+	var mu sync.Mutex
+	benchmarkLocker(b, &mu)
+}
+
+func benchmarkLocker(b *testing.B, l sync.Locker) {
+	b.Run("uncontended", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			l.Lock()
+			l.Unlock()
+		}
+	})
+
+	b.Run("parallel", func(b *testing.B) {
+		var n int
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				l.Lock()
+				n++
+				l.Unlock()
+			}
+		})
+	})
+}
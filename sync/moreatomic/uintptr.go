@@ -0,0 +1,65 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreatomic
+
+import (
+	"sync/atomic"
+	"unsafe"
+)
+
+func AddUintptr(addr *uintptr, delta uintptr) (new uintptr) {
+	switch unsafe.Sizeof(*addr) {
+	case 4:
+		return uintptr(atomic.AddUint32((*uint32)(unsafe.Pointer(addr)), uint32(delta)))
+	case 8:
+		return uintptr(atomic.AddUint64((*uint64)(unsafe.Pointer(addr)), uint64(delta)))
+	default:
+		panic("uintptr is neither 4 nor 8 bytes")
+	}
+}
+
+func CompareAndSwapUintptr(addr *uintptr, old, new uintptr) (swapped bool) {
+	switch unsafe.Sizeof(*addr) {
+	case 4:
+		return atomic.CompareAndSwapUint32((*uint32)(unsafe.Pointer(addr)), uint32(old), uint32(new))
+	case 8:
+		return atomic.CompareAndSwapUint64((*uint64)(unsafe.Pointer(addr)), uint64(old), uint64(new))
+	default:
+		panic("uintptr is neither 4 nor 8 bytes")
+	}
+}
+
+func LoadUintptr(addr *uintptr) (val uintptr) {
+	switch unsafe.Sizeof(*addr) {
+	case 4:
+		return uintptr(atomic.LoadUint32((*uint32)(unsafe.Pointer(addr))))
+	case 8:
+		return uintptr(atomic.LoadUint64((*uint64)(unsafe.Pointer(addr))))
+	default:
+		panic("uintptr is neither 4 nor 8 bytes")
+	}
+}
+
+func StoreUintptr(addr *uintptr, val uintptr) {
+	switch unsafe.Sizeof(*addr) {
+	case 4:
+		atomic.StoreUint32((*uint32)(unsafe.Pointer(addr)), uint32(val))
+	case 8:
+		atomic.StoreUint64((*uint64)(unsafe.Pointer(addr)), uint64(val))
+	default:
+		panic("uintptr is neither 4 nor 8 bytes")
+	}
+}
+
+func SwapUintptr(addr *uintptr, new uintptr) (old uintptr) {
+	switch unsafe.Sizeof(*addr) {
+	case 4:
+		return uintptr(atomic.SwapUint32((*uint32)(unsafe.Pointer(addr)), uint32(new)))
+	case 8:
+		return uintptr(atomic.SwapUint64((*uint64)(unsafe.Pointer(addr)), uint64(new)))
+	default:
+		panic("uintptr is neither 4 nor 8 bytes")
+	}
+}
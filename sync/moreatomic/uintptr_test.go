@@ -0,0 +1,50 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package moreatomic_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/bcmills/more/sync/moreatomic"
+)
+
+func TestUintptr(t *testing.T) {
+	var p uintptr
+	if got := moreatomic.LoadUintptr(&p); got != 0 {
+		t.Fatalf("LoadUintptr(&0) = %v", got)
+	}
+	moreatomic.StoreUintptr(&p, ^uintptr(0))
+	if got := moreatomic.AddUintptr(&p, 1); got != 0 {
+		t.Fatalf("AddUintptr(&max, 1) = %v; want 0 (wrapped)", got)
+	}
+	if old := moreatomic.SwapUintptr(&p, 5); old != 0 {
+		t.Fatalf("SwapUintptr(&0, 5) = %v; want 0", old)
+	}
+	if moreatomic.CompareAndSwapUintptr(&p, 0, 1) || !moreatomic.CompareAndSwapUintptr(&p, 5, 7) || p != 7 {
+		t.Fatalf("CompareAndSwapUintptr did not behave as expected")
+	}
+}
+
+func TestAddUintptrConcurrent(t *testing.T) {
+	var (
+		p  uintptr
+		wg sync.WaitGroup
+	)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				moreatomic.AddUintptr(&p, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := moreatomic.LoadUintptr(&p); got != 8000 {
+		t.Fatalf("LoadUintptr = %v; want 8000", got)
+	}
+}
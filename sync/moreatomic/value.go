@@ -0,0 +1,59 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.18
+// +build go1.18
+
+package moreatomic
+
+// A Value provides an atomic load and store of a value of type T.
+// The zero Value holds no value.
+//
+// Unlike atomic.Value, a Value may store values of differing dynamic types
+// (such as when T is an interface type), because each value is boxed
+// internally.
+type Value[T any] struct {
+	p Pointer[box[T]]
+}
+
+type box[T any] struct {
+	v T
+}
+
+// Load returns the value set by the most recent Store,
+// or the zero value of T and false if there has been no call to Store.
+func (x *Value[T]) Load() (val T, ok bool) {
+	b := x.p.Load()
+	if b == nil {
+		return val, false
+	}
+	return b.v, true
+}
+
+// Store sets the value of x to val.
+func (x *Value[T]) Store(val T) {
+	x.p.Store(&box[T]{v: val})
+}
+
+// CompareAndSwap executes the compare-and-swap operation for x.
+// A Value that holds no value compares equal to the zero value of T.
+//
+// As with atomic.Value, CompareAndSwap panics if the current value and old are
+// of the same non-comparable dynamic type.
+func (x *Value[T]) CompareAndSwap(old, new T) (swapped bool) {
+	nb := &box[T]{v: new}
+	for {
+		b := x.p.Load()
+		var cur T
+		if b != nil {
+			cur = b.v
+		}
+		if any(cur) != any(old) {
+			return false
+		}
+		if x.p.CompareAndSwap(b, nb) {
+			return true
+		}
+	}
+}
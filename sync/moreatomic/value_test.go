@@ -0,0 +1,56 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.18
+// +build go1.18
+
+package moreatomic_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/bcmills/more/sync/moreatomic"
+)
+
+func TestValue(t *testing.T) {
+	var x moreatomic.Value[string]
+	if v, ok := x.Load(); v != "" || ok {
+		t.Fatalf("zero Value: Load() = %q, %v; want \"\", false", v, ok)
+	}
+
+	x.Store("a")
+	if v, ok := x.Load(); v != "a" || !ok {
+		t.Fatalf(`after Store("a"): Load() = %q, %v; want "a", true`, v, ok)
+	}
+
+	if x.CompareAndSwap("b", "c") {
+		t.Fatalf(`CompareAndSwap("b", "c") succeeded with value "a"`)
+	}
+	if !x.CompareAndSwap("a", "b") {
+		t.Fatalf(`CompareAndSwap("a", "b") failed with value "a"`)
+	}
+	if v, _ := x.Load(); v != "b" {
+		t.Fatalf(`after CompareAndSwap("a", "b"): Load() = %q; want "b"`, v)
+	}
+}
+
+func TestValueDifferingDynamicTypes(t *testing.T) {
+	var x moreatomic.Value[error]
+	if !x.CompareAndSwap(nil, errors.New("first")) {
+		t.Fatalf("CompareAndSwap(nil, _) failed on zero Value")
+	}
+
+	wrapped := fmt.Errorf("wrapped: %w", errors.New("second"))
+	x.Store(wrapped)
+	if v, ok := x.Load(); v != wrapped || !ok {
+		t.Fatalf("Load() = %v, %v; want %v, true", v, ok, wrapped)
+	}
+
+	x.Store(nil)
+	if v, ok := x.Load(); v != nil || !ok {
+		t.Fatalf("after Store(nil): Load() = %v, %v; want <nil>, true", v, ok)
+	}
+}